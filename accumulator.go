@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"github.com/jpascal/zap-errors/core"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sync"
+)
+
+// Accumulator collects the non-fatal errors of one request, such as soft
+// failures and degraded lookups, to log them as a single entry when the
+// request ends instead of one entry each:
+//
+//	acc, ctx := errors.NewAccumulator(r.Context())
+//	defer acc.Log(logger)
+//	...
+//	errors.Accumulate(ctx, err)
+type Accumulator struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+type accumulatorKey struct{}
+
+// NewAccumulator returns an Accumulator and a context derived from ctx
+// carrying it, for Accumulate.
+func NewAccumulator(ctx context.Context) (*Accumulator, context.Context) {
+	acc := &Accumulator{}
+	return acc, context.WithValue(ctx, accumulatorKey{}, acc)
+}
+
+// AccumulatorFrom returns the Accumulator carried by ctx.
+func AccumulatorFrom(ctx context.Context) (*Accumulator, bool) {
+	acc, ok := ctx.Value(accumulatorKey{}).(*Accumulator)
+	return acc, ok
+}
+
+// Accumulate adds err to the Accumulator carried by ctx. It reports false
+// when ctx carries none, leaving err to the caller.
+func Accumulate(ctx context.Context, err error) bool {
+	acc, ok := AccumulatorFrom(ctx)
+	if ok {
+		acc.add(err, 1)
+	}
+	return ok
+}
+
+// Add collects err, given a stacktrace if it does not carry one already. Nil
+// errors are ignored.
+func (a *Accumulator) Add(err error) {
+	a.add(err, 1)
+}
+
+func (a *Accumulator) add(err error, skip int) {
+	if err == nil {
+		return
+	}
+	err = ensureStack(err, skip+1)
+	a.mu.Lock()
+	a.errs = append(a.errs, err)
+	a.mu.Unlock()
+}
+
+// Len returns the number of collected errors.
+func (a *Accumulator) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.errs)
+}
+
+// Err returns the collected errors as one warning, their details kept as
+// children like WrapAll does, or nil when none was collected.
+func (a *Accumulator) Err() error {
+	a.mu.Lock()
+	errs := a.errs[:len(a.errs):len(a.errs)]
+	a.mu.Unlock()
+	return accumulated(errs)
+}
+
+func accumulated(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	children := make([]child, 0, len(errs))
+	for index, err := range errs {
+		children = append(children, child{index: index, err: err})
+	}
+	statsCreated()
+	return Error{
+		base:        core.New(fmt.Sprintf("%d non-fatal errors", len(children)), nil),
+		children:    children,
+		severity:    zapcore.WarnLevel,
+		hasSeverity: true,
+		mark:        newMark(),
+	}
+}
+
+// Log logs the collected errors as a single entry, see Err, and resets the
+// Accumulator. Errors added concurrently are either logged or kept for the
+// next call. It does nothing when no error was collected.
+func (a *Accumulator) Log(logger *zap.Logger) {
+	a.mu.Lock()
+	errs := a.errs
+	a.errs = nil
+	a.mu.Unlock()
+	Log(logger, accumulated(errs))
+}
@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"sync"
+	"testing"
+)
+
+func TestAccumulatorLogKeepsConcurrentErrors(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	acc := &Accumulator{}
+	const adders, adds = 8, 100
+	var wg sync.WaitGroup
+	for i := 0; i < adders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < adds; j++ {
+				acc.Add(Errorf("soft failure"))
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		acc.Log(logger)
+	}
+
+	logged := 0
+	for _, entry := range logs.AllUntimed() {
+		object := entry.ContextMap()["error"].(map[string]interface{})
+		logged += len(object["errors"].([]interface{}))
+	}
+	if logged != adders*adds {
+		t.Errorf("logged %d errors, want %d", logged, adders*adds)
+	}
+}
@@ -0,0 +1,74 @@
+package errors
+
+// Decision tells a message consumer how to settle a message.
+type Decision int
+
+const (
+	// Ack acknowledges the message, processing succeeded.
+	Ack Decision = iota
+	// Nack rejects the message for good, typically to a dead-letter queue.
+	Nack
+	// Requeue rejects the message for it to be delivered again.
+	Requeue
+)
+
+func (d Decision) String() string {
+	switch d {
+	case Ack:
+		return "ack"
+	case Nack:
+		return "nack"
+	case Requeue:
+		return "requeue"
+	}
+	return "unknown"
+}
+
+// AckDecision centralizes the requeue policy of consumers: successes are
+// acknowledged, retryable failures requeued, everything else rejected so
+// poison messages do not loop forever.
+func AckDecision(err error) Decision {
+	switch {
+	case err == nil:
+		return Ack
+	case IsRetryable(err) && !IsPermanent(err):
+		return Requeue
+	}
+	return Nack
+}
+
+// JetStreamMsg is implemented by messages of github.com/nats-io/nats.go/jetstream.
+type JetStreamMsg interface {
+	Ack() error
+	Nak() error
+	Term() error
+}
+
+// SettleJetStream settles msg according to AckDecision: requeued messages are
+// negatively acknowledged for redelivery, rejected ones terminated.
+func SettleJetStream(msg JetStreamMsg, err error) error {
+	switch AckDecision(err) {
+	case Ack:
+		return msg.Ack()
+	case Requeue:
+		return msg.Nak()
+	}
+	return msg.Term()
+}
+
+// AMQPDelivery is implemented by amqp091.Delivery.
+type AMQPDelivery interface {
+	Ack(multiple bool) error
+	Nack(multiple, requeue bool) error
+}
+
+// SettleAMQP settles delivery according to AckDecision.
+func SettleAMQP(delivery AMQPDelivery, err error) error {
+	switch AckDecision(err) {
+	case Ack:
+		return delivery.Ack(false)
+	case Requeue:
+		return delivery.Nack(false, true)
+	}
+	return delivery.Nack(false, false)
+}
@@ -0,0 +1,28 @@
+package errors
+
+import "go.uber.org/zap/zapcore"
+
+// ZerologDict renders err the way Field does, as a map suitable for
+// zerolog's Event.Interface("error", ...) or Event.Fields. It returns nil for
+// a nil error.
+func ZerologDict(err error) map[string]interface{} {
+	if err == nil {
+		return nil
+	}
+	encoder := zapcore.NewMapObjectEncoder()
+	Field(err).AddTo(encoder)
+	object, _ := encoder.Fields["error"].(map[string]interface{})
+	if object == nil {
+		object = map[string]interface{}{"message": err.Error()}
+	}
+	return object
+}
+
+// LogrusFields renders err the way Field does, ready to be passed to
+// logrus.WithFields.
+func LogrusFields(err error) map[string]interface{} {
+	if err == nil {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{"error": ZerologDict(err)}
+}
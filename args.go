@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+)
+
+type frameArg struct {
+	function string
+	key      string
+	value    interface{}
+}
+
+// DebugArgs enables WithArg. It is meant for debug builds: arguments end up
+// in the logs next to the frame that recorded them.
+func DebugArgs(enabled bool) Option {
+	return func(c *config) {
+		c.debugArgs = enabled
+	}
+}
+
+// WithArg records an argument of the calling function, rendered with its
+// frame in the stacktrace, to reconstruct the inputs of the failing call. It
+// does nothing unless DebugArgs is enabled.
+func (ee Error) WithArg(key string, value interface{}) Error {
+	if !current().debugArgs {
+		return ee
+	}
+	function := ""
+	if pc, _, _, ok := runtime.Caller(1); ok {
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			function = fn.Name()
+		}
+	}
+	args := make([]frameArg, len(ee.args), len(ee.args)+1)
+	copy(args, ee.args)
+	ee.args = append(args, frameArg{function: function, key: key, value: value})
+	return ee.enriched()
+}
+
+// writeStack renders frames in the stack format of conf.
+func writeStack(w io.Writer, frames []*runtime.Frame, args []frameArg, conf *config) {
+	if conf.stackFormat == StackFormatZap {
+		writeZapStack(w, frames)
+		return
+	}
+	writeDefaultStack(w, frames, args)
+}
+
+// writeDefaultStack renders frames in the default stacktrace format, with the
+// arguments recorded for a frame right after it. Arguments of functions
+// missing from frames come last.
+func writeDefaultStack(w io.Writer, frames []*runtime.Frame, args []frameArg) {
+	rendered := make([]bool, len(args))
+	// Frames are written without fmt, which would allocate for each of their
+	// strings, through a buffer large enough for most of them.
+	line := make([]byte, 0, 256)
+	for _, frame := range frames {
+		line = append(line[:0], frame.Function...)
+		line = append(line, '\t', '\n')
+		line = append(line, frame.File...)
+		line = append(line, ':')
+		line = strconv.AppendInt(line, int64(frame.Line), 10)
+		line = append(line, '\n')
+		_, _ = w.Write(line)
+		for i, arg := range args {
+			if !rendered[i] && arg.function == frame.Function {
+				_, _ = fmt.Fprintf(w, "\t%s=%v\n", arg.key, arg.value)
+				rendered[i] = true
+			}
+		}
+	}
+	for i, arg := range args {
+		if !rendered[i] {
+			_, _ = fmt.Fprintf(w, "%s\t\n\t%s=%v\n", arg.function, arg.key, arg.value)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package errors
+
+import "go.uber.org/zap"
+
+// AuditEntry is the restricted view of an error handed to audit sinks. It
+// deliberately leaves out payloads and stacktraces.
+type AuditEntry struct {
+	Message string
+	Code    int
+	Kind    Kind
+}
+
+// AuditSink receives errors marked with WithAudit when they are logged.
+type AuditSink interface {
+	Audit(entry AuditEntry)
+}
+
+// Audit sets the sink security-relevant errors are duplicated to by Log.
+func Audit(sink AuditSink) Option {
+	return func(c *config) {
+		c.audit = sink
+	}
+}
+
+// WithAudit marks the error as security relevant, so Log also hands it to the
+// AuditSink set with Audit.
+func (ee Error) WithAudit(audit bool) Error {
+	ee.audit = audit
+	return ee.enriched()
+}
+
+func audit(err error) {
+	sink := current().audit
+	if sink == nil {
+		return
+	}
+	var ee Error
+	if asError(err, &ee) && ee.audit {
+		sink.Audit(AuditEntry{Message: ee.base.Message(), Code: resolveCode(err, current()), Kind: ee.kind})
+	}
+}
+
+type auditLogger struct {
+	logger *zap.Logger
+}
+
+// NewAuditLogger returns an AuditSink writing entries to a dedicated logger.
+func NewAuditLogger(logger *zap.Logger) AuditSink {
+	return auditLogger{logger: logger}
+}
+
+func (al auditLogger) Audit(entry AuditEntry) {
+	fields := []zap.Field{zap.Int("code", entry.Code)}
+	if entry.Kind != KindUnknown {
+		fields = append(fields, zap.String("kind", string(entry.Kind)))
+	}
+	al.logger.Warn(entry.Message, fields...)
+}
@@ -0,0 +1,83 @@
+package errors
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"sync/atomic"
+	"time"
+)
+
+// Permanent marks err as not worth retrying. Retry loops, such as the ones of
+// github.com/cenkalti/backoff, can stop on IsPermanent.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return promote(err).WithRetryable(false)
+}
+
+// IsPermanent reports whether err was marked with Permanent or
+// WithRetryable(false) at any layer.
+func IsPermanent(err error) bool {
+	permanent := false
+	Walk(err, func(err error) bool {
+		if ee, ok := errorOf(err); ok {
+			permanent = ee.retry == retryNo
+		}
+		return !permanent
+	})
+	return permanent
+}
+
+// RetryNotify returns a notify function for backoff.RetryNotify style retry
+// loops, logging every failed attempt at warn level with its number and the
+// delay before the next one.
+func RetryNotify(logger *zap.Logger) func(err error, next time.Duration) {
+	var attempts int64
+	return func(err error, next time.Duration) {
+		attempt := atomic.AddInt64(&attempts, 1)
+		logger.Warn(err.Error(), Field(err), zap.Int64("attempt", attempt), zap.Duration("next_delay", next))
+	}
+}
+
+// WithAttempt records that the error ended attempt n, counted from 1, out of
+// max as the attempt and max_attempts fields. max_attempts is left out when
+// max is not positive.
+func (ee Error) WithAttempt(n, max int) Error {
+	fields := map[string]interface{}{"attempt": n}
+	if max > 0 {
+		fields["max_attempts"] = max
+	}
+	return ee.WithFields(fields)
+}
+
+// Retry calls op until it succeeds, returns a permanent error, see
+// IsPermanent, or max attempts were made, waiting delay(attempt) after each
+// failed attempt. Waiting stops when ctx is done. The error returned is the
+// last one, stamped with the number of attempts, see WithAttempt, and the
+// cumulative delay as total_delay.
+func Retry(ctx context.Context, max int, delay func(attempt int) time.Duration, op func() error) error {
+	var total time.Duration
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		if IsPermanent(err) || (max > 0 && attempt >= max) || ctx.Err() != nil {
+			return retried(err, attempt, max, total)
+		}
+		wait := delay(attempt)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return retried(err, attempt, max, total)
+		case <-timer.C:
+			total += wait
+		}
+	}
+}
+
+func retried(err error, attempt, max int, total time.Duration) error {
+	return promote(ensureStack(err, 2)).WithAttempt(attempt, max).WithField("total_delay", total)
+}
@@ -0,0 +1,30 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRetryKeepsInnerStacktrace(t *testing.T) {
+	inner := Errorf("connection refused")
+	wrapped := fmt.Errorf("dialing: %w", inner)
+	for name, err := range map[string]error{
+		"Permanent": Permanent(wrapped),
+		"Retry": Retry(context.Background(), 2, func(int) time.Duration { return 0 }, func() error {
+			return wrapped
+		}),
+	} {
+		var ee Error
+		if !asError(err, &ee) {
+			t.Fatalf("%s: got %T, want an Error", name, err)
+		}
+		if len(ee.stacktrace) == 0 || ee.stacktrace[0] != inner.stacktrace[0] {
+			t.Errorf("%s: the stacktrace of the inner error is lost: %v", name, ee.stacktrace)
+		}
+		if ee.Error() != wrapped.Error() {
+			t.Errorf("%s: got message %q, want %q", name, ee.Error(), wrapped.Error())
+		}
+	}
+}
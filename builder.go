@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"fmt"
+	"github.com/jpascal/zap-errors/core"
+	"go.uber.org/zap/zapcore"
+)
+
+// Builder assembles an Error in place, for hot paths where chaining With*
+// methods would copy the whole Error at every step. The stacktrace is
+// captured once by Build or Err:
+//
+//	return errors.NewBuilder("reading %s", name).Code(42).Field("size", n).Err()
+//
+// A Builder can be reused after Build, later changes do not affect the Errors
+// already built.
+type Builder struct {
+	ee     Error
+	shared bool
+}
+
+// NewBuilder starts an Error with a message formatted like Errorf.
+func NewBuilder(format string, a ...interface{}) *Builder {
+	message := fmt.Sprintf(format, a...)
+	return &Builder{ee: Error{base: core.New(message, fmt.Errorf(format, a...))}}
+}
+
+// Code sets the code, see Error.WithCode.
+func (b *Builder) Code(code int) *Builder {
+	b.ee.base = b.ee.base.WithCode(code)
+	return b
+}
+
+// Kind sets the kind, see Error.WithKind.
+func (b *Builder) Kind(kind Kind) *Builder {
+	b.ee.kind = kind
+	return b
+}
+
+// Payload attaches a payload, see Error.WithPayload.
+func (b *Builder) Payload(payload interface{}) *Builder {
+	b.ee.base = b.ee.base.WithPayload(payload)
+	return b
+}
+
+// Severity sets the level the error is logged at, see Error.WithSeverity.
+func (b *Builder) Severity(level zapcore.Level) *Builder {
+	b.ee.severity = level
+	b.ee.hasSeverity = true
+	return b
+}
+
+// Field attaches a structured field, see Error.WithField.
+func (b *Builder) Field(key string, value interface{}) *Builder {
+	if b.ee.fields == nil || b.shared {
+		fields := make(map[string]interface{}, len(b.ee.fields)+1)
+		for k, v := range b.ee.fields {
+			fields[k] = v
+		}
+		b.ee.fields = fields
+		b.shared = false
+	}
+	b.ee.fields[key] = value
+	return b
+}
+
+// Build returns the Error, capturing its stacktrace.
+func (b *Builder) Build() Error {
+	return b.build(1)
+}
+
+// Err is like Build but returns an error.
+func (b *Builder) Err() error {
+	return b.build(1)
+}
+
+func (b *Builder) build(skip int) Error {
+	statsCreated()
+	ee := b.ee
+	ee.stacktrace = stackTraceAt(ee.level(), skip)
+	ee.mark = newMark()
+	b.shared = true
+	return ee
+}
@@ -0,0 +1,161 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jpascal/zap-errors/core"
+	"go.uber.org/zap/zapcore"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Definition describes an error code of the catalog.
+type Definition struct {
+	// Name is the Go identifier of the code in generated sources, such as
+	// UserNotFound.
+	Name string
+	Code int
+	Kind Kind
+	// Message is the default message, a format for New.
+	Message string
+}
+
+var catalog struct {
+	mu          sync.RWMutex
+	definitions []Definition
+}
+
+// Define registers def. It fails when its name or code is already defined.
+func Define(def Definition) error {
+	if def.Name == "" {
+		return Errorf("definition of code %d has no name", def.Code).WithKind(KindInvalid)
+	}
+	catalog.mu.Lock()
+	defer catalog.mu.Unlock()
+	for _, d := range catalog.definitions {
+		if d.Name == def.Name || d.Code == def.Code {
+			return Errorf("definition %s (%d) collides with %s (%d)",
+				def.Name, def.Code, d.Name, d.Code).WithKind(KindConflict)
+		}
+	}
+	catalog.definitions = append(catalog.definitions, def)
+	sort.Slice(catalog.definitions, func(i, j int) bool {
+		return catalog.definitions[i].Code < catalog.definitions[j].Code
+	})
+	return nil
+}
+
+// MustDefine is Define panicking on collisions, for use in package variable
+// declarations. It returns def.
+func MustDefine(def Definition) Definition {
+	if err := Define(def); err != nil {
+		panic(err)
+	}
+	return def
+}
+
+// Definitions returns the registered definitions ordered by code.
+func Definitions() []Definition {
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+	return append([]Definition(nil), catalog.definitions...)
+}
+
+// New creates an Error with the code and kind of the definition, formatting
+// its message with a.
+func (d Definition) New(a ...interface{}) Error {
+	ee := errorf(zapcore.ErrorLevel, 1, d.Message, a...)
+	ee.base = ee.base.WithCode(d.Code)
+	ee.kind = d.Kind
+	return ee
+}
+
+// CatalogEntry documents a Definition with the statuses its errors map to.
+type CatalogEntry struct {
+	Name       string `json:"name"`
+	Code       int    `json:"code"`
+	Kind       Kind   `json:"kind,omitempty"`
+	Message    string `json:"message"`
+	Subsystem  string `json:"subsystem,omitempty"`
+	HTTPStatus int    `json:"http_status"`
+	GRPCCode   string `json:"grpc_code"`
+}
+
+// CatalogFormat selects the format written by WriteCatalog.
+type CatalogFormat int
+
+const (
+	CatalogJSON CatalogFormat = iota
+	CatalogMarkdown
+)
+
+var kindGRPCCodes = map[Kind]string{
+	KindInvalid:          "INVALID_ARGUMENT",
+	KindNotFound:         "NOT_FOUND",
+	KindConflict:         "ALREADY_EXISTS",
+	KindUnauthenticated:  "UNAUTHENTICATED",
+	KindPermissionDenied: "PERMISSION_DENIED",
+	KindCanceled:         "CANCELLED",
+	KindTimeout:          "DEADLINE_EXCEEDED",
+	KindUnavailable:      "UNAVAILABLE",
+	KindTLS:              "UNAVAILABLE",
+	KindInternal:         "INTERNAL",
+}
+
+// Catalog documents the registered definitions ordered by code.
+func Catalog() []CatalogEntry {
+	definitions := Definitions()
+	entries := make([]CatalogEntry, 0, len(definitions))
+	for _, def := range definitions {
+		entry := CatalogEntry{
+			Name:       def.Name,
+			Code:       def.Code,
+			Kind:       def.Kind,
+			Message:    def.Message,
+			HTTPStatus: StatusOf(Error{base: core.New("", nil).WithCode(def.Code), kind: def.Kind}),
+			GRPCCode:   "UNKNOWN",
+		}
+		entry.Subsystem, _ = SubsystemOf(def.Code)
+		if code, ok := kindGRPCCodes[def.Kind]; ok {
+			entry.GRPCCode = code
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// WriteCatalog writes the Catalog, for API documentation generated at start
+// up or from a command of the program:
+//
+//	if len(os.Args) > 1 && os.Args[1] == "error-catalog" {
+//		if err := errors.WriteCatalog(os.Stdout, errors.CatalogMarkdown); err != nil {
+//			log.Fatal(err)
+//		}
+//		return
+//	}
+func WriteCatalog(w io.Writer, format CatalogFormat) error {
+	entries := Catalog()
+	if format == CatalogJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+	builder := &strings.Builder{}
+	builder.WriteString("| Code | Name | Kind | Message | Subsystem | HTTP | gRPC |\n")
+	builder.WriteString("| ---: | --- | --- | --- | --- | ---: | --- |\n")
+	for _, entry := range entries {
+		_, _ = fmt.Fprintf(builder, "| %d | %s | %s | %s | %s | %d | %s |\n",
+			entry.Code, markdownCell(entry.Name), entry.Kind, markdownCell(entry.Message),
+			markdownCell(entry.Subsystem), entry.HTTPStatus, entry.GRPCCode)
+	}
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+func markdownCell(text string) string {
+	return markdownEscaper.Replace(text)
+}
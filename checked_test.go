@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+)
+
+// countingError counts the calls to Error.
+type countingError struct {
+	calls *int
+}
+
+func (e countingError) Error() string {
+	*e.calls++
+	return "failure"
+}
+
+func TestCheckedLogDisabledLevel(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+	calls := 0
+	err := error(countingError{calls: &calls})
+
+	CheckedLog(logger, zapcore.DebugLevel, err)
+	if calls != 0 || logs.Len() != 0 {
+		t.Errorf("disabled level: %d calls to Error, %d entries", calls, logs.Len())
+	}
+
+	CheckedLog(logger, zapcore.WarnLevel, err)
+	if logs.Len() != 1 || logs.AllUntimed()[0].Level != zapcore.WarnLevel {
+		t.Errorf("enabled level: got %v", logs.AllUntimed())
+	}
+}
+
+func TestCheckedLogNilLogger(t *testing.T) {
+	CheckedLog(nil, zapcore.ErrorLevel, Errorf("failure"))
+}
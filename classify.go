@@ -0,0 +1,110 @@
+package errors
+
+type retryability int8
+
+const (
+	retryUnknown retryability = iota
+	retryYes
+	retryNo
+)
+
+func (ee Error) WithRetryable(retryable bool) Error {
+	ee.retry = retryNo
+	if retryable {
+		ee.retry = retryYes
+	}
+	return ee.enriched()
+}
+
+// IsRetryable reports whether retrying the failed operation may succeed. An
+// explicit WithRetryable wins, otherwise timeouts and unavailability are
+// retryable.
+func IsRetryable(err error) bool {
+	var ee Error
+	if !asError(err, &ee) {
+		return false
+	}
+	switch ee.retry {
+	case retryYes:
+		return true
+	case retryNo:
+		return false
+	}
+	return ee.kind == KindTimeout || ee.kind == KindUnavailable
+}
+
+var clientKinds = map[Kind]bool{
+	KindInvalid:          true,
+	KindNotFound:         true,
+	KindConflict:         true,
+	KindUnauthenticated:  true,
+	KindPermissionDenied: true,
+	KindCanceled:         true,
+}
+
+// IsClientError reports whether err was caused by the caller. The kind
+// decides when set, otherwise codes in the 400-499 range are taken as HTTP
+// statuses.
+func IsClientError(err error) bool {
+	var ee Error
+	if !asError(err, &ee) {
+		return false
+	}
+	if ee.kind != KindUnknown {
+		return clientKinds[ee.kind]
+	}
+	code := resolveCode(err, current())
+	return code >= 400 && code < 500
+}
+
+// IsServerError reports whether err is a failure on our side, which is the
+// case for every non-nil error that is not a client error.
+func IsServerError(err error) bool {
+	return err != nil && !IsClientError(err)
+}
+
+// CountsAgainstBreaker is a predicate for circuit breakers: retryable
+// failures and server errors count, client errors do not.
+func CountsAgainstBreaker(err error) bool {
+	if err == nil {
+		return false
+	}
+	return IsRetryable(err) || IsServerError(err)
+}
+
+// Classes returned by Classify.
+const (
+	ClassTimeout    = "timeout"
+	ClassDependency = "dependency"
+	ClassValidation = "validation"
+	ClassPanic      = "panic"
+	ClassDisconnect = "disconnect"
+	ClassUnknown    = "unknown"
+)
+
+// Classify sorts err into a small stable set of classes meant for alert
+// routing, logged as class. It returns an empty string for nil.
+func Classify(err error) string {
+	if err == nil {
+		return ""
+	}
+	if IsDisconnect(err) {
+		return ClassDisconnect
+	}
+	if IsPanic(err) {
+		return ClassPanic
+	}
+	var ee Error
+	asError(err, &ee)
+	switch {
+	case ee.kind == KindTimeout || ee.kind == KindCanceled:
+		return ClassTimeout
+	case ee.kind == KindUnavailable || ee.kind == KindTLS:
+		return ClassDependency
+	case hasDependency(err):
+		return ClassDependency
+	case IsClientError(err):
+		return ClassValidation
+	}
+	return ClassUnknown
+}
@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+)
+
+// GenerateCodes writes the Go source of package pkg declaring the registered
+// definitions as constants of type typeName, with a String method and a
+// lookup table from names to codes, so code literals stay out of application
+// sources. It is meant to be run by go:generate through a small program
+// importing the packages defining the codes:
+//
+//	//go:generate go run ./internal/gencodes
+//
+//	func main() {
+//		if err := errors.GenerateCodes(os.Stdout, "codes", "Code"); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+func GenerateCodes(w io.Writer, pkg, typeName string) error {
+	definitions := Definitions()
+	var source bytes.Buffer
+	_, _ = fmt.Fprintf(&source, "// Code generated by GenerateCodes. DO NOT EDIT.\n\npackage %s\n\nimport \"strconv\"\n\n", pkg)
+	_, _ = fmt.Fprintf(&source, "type %s int\n\n", typeName)
+	source.WriteString("const (\n")
+	for _, def := range definitions {
+		_, _ = fmt.Fprintf(&source, "\t%s %s = %d\n", def.Name, typeName, def.Code)
+	}
+	source.WriteString(")\n\n")
+	lookup := "lookup" + typeName
+	_, _ = fmt.Fprintf(&source, "// %s maps the names of the codes to their value.\n", lookup)
+	_, _ = fmt.Fprintf(&source, "var %s = map[string]%s{\n", lookup, typeName)
+	for _, def := range definitions {
+		_, _ = fmt.Fprintf(&source, "\t%s: %s,\n", strconv.Quote(def.Name), def.Name)
+	}
+	source.WriteString("}\n\n")
+	_, _ = fmt.Fprintf(&source, "func (c %s) String() string {\n\tswitch c {\n", typeName)
+	for _, def := range definitions {
+		_, _ = fmt.Fprintf(&source, "\tcase %s:\n\t\treturn %s\n", def.Name, strconv.Quote(def.Name))
+	}
+	_, _ = fmt.Fprintf(&source, "\t}\n\treturn %s + strconv.Itoa(int(c)) + \")\"\n}\n\n", strconv.Quote(typeName+"("))
+	_, _ = fmt.Fprintf(&source, "// Parse%s returns the code named name.\n", typeName)
+	_, _ = fmt.Fprintf(&source, "func Parse%s(name string) (%s, bool) {\n\tc, ok := %s[name]\n\treturn c, ok\n}\n", typeName, typeName, lookup)
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(formatted)
+	return err
+}
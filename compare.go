@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Equal reports whether a and b carry the same message, code, kind and
+// payload, all the way down their chains. Stacktraces are ignored.
+func Equal(a, b error) bool {
+	return len(diff(a, b, "")) == 0
+}
+
+// Diff describes the differences Equal would find, one per line. It returns
+// an empty string for equal errors.
+func Diff(a, b error) string {
+	return strings.Join(diff(a, b, ""), "\n")
+}
+
+func diff(a, b error, path string) []string {
+	if a == nil || b == nil {
+		if a == b {
+			return nil
+		}
+		return []string{fmt.Sprintf("%serror: %v != %v", path, a, b)}
+	}
+	var ea, eb Error
+	okA, okB := asError(a, &ea), asError(b, &eb)
+	if !okA || !okB {
+		if okA != okB {
+			return []string{fmt.Sprintf("%stype: %T != %T", path, a, b)}
+		}
+		if a.Error() != b.Error() {
+			return []string{fmt.Sprintf("%smessage: %q != %q", path, a.Error(), b.Error())}
+		}
+		return nil
+	}
+	var diffs []string
+	if ea.base.Message() != eb.base.Message() {
+		diffs = append(diffs, fmt.Sprintf("%smessage: %q != %q", path, ea.base.Message(), eb.base.Message()))
+	}
+	if ea.base.Code() != eb.base.Code() {
+		diffs = append(diffs, fmt.Sprintf("%scode: %d != %d", path, ea.base.Code(), eb.base.Code()))
+	}
+	if ea.kind != eb.kind {
+		diffs = append(diffs, fmt.Sprintf("%skind: %q != %q", path, ea.kind, eb.kind))
+	}
+	if !reflect.DeepEqual(ea.base.Payload(), eb.base.Payload()) {
+		diffs = append(diffs, fmt.Sprintf("%spayload: %+v != %+v", path, ea.base.Payload(), eb.base.Payload()))
+	}
+	return append(diffs, diff(ea.base.Unwrap(), eb.base.Unwrap(), path+"cause.")...)
+}
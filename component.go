@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"runtime"
+	"strings"
+)
+
+const packagePath = "github.com/jpascal/zap-errors"
+
+// ComponentPolicy derives the component owning an error from its stacktrace:
+// the package of the innermost frame that is not part of a framework. Skip
+// lists package path prefixes considered framework code, the runtime and this
+// package are always skipped. Names maps package path prefixes to component
+// names, the longest matching prefix wins; unmapped packages are reported by
+// their path.
+type ComponentPolicy struct {
+	Skip  []string
+	Names map[string]string
+}
+
+// Components enables the component field of logged errors.
+func Components(policy ComponentPolicy) Option {
+	return func(c *config) {
+		c.components = &policy
+	}
+}
+
+func (p *ComponentPolicy) component(frames []*runtime.Frame) (string, bool) {
+	for _, frame := range frames {
+		pkg := packageOf(frame.Function)
+		if pkg == "" || p.skipped(pkg) {
+			continue
+		}
+		name, length := pkg, -1
+		for prefix, component := range p.Names {
+			if hasPathPrefix(pkg, prefix) && len(prefix) > length {
+				name, length = component, len(prefix)
+			}
+		}
+		return name, true
+	}
+	return "", false
+}
+
+func (p *ComponentPolicy) skipped(pkg string) bool {
+	if pkg == "runtime" || hasPathPrefix(pkg, packagePath) {
+		return true
+	}
+	for _, prefix := range p.Skip {
+		if hasPathPrefix(pkg, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func packageOf(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return ""
+}
+
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
+}
@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentEnrichment is meant to be run with -race: goroutines
+// enriching the same base Error must not see each other's changes.
+func TestConcurrentEnrichment(t *testing.T) {
+	defer CurrentConfig().Apply()
+	Configure(DebugArgs(true))
+	base := Errorf("base").WithField("shared", true)
+	logger := zap.NewNop()
+	const goroutines = 16
+	results := make([]Error, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ee := base.WithField("goroutine", i).WithArg("i", i)
+			ee = WithMessage(ee, fmt.Sprintf("goroutine %d", i)).WithField(fmt.Sprintf("own.%d", i), i)
+			Log(logger, ee)
+			results[i] = ee
+		}(i)
+	}
+	wg.Wait()
+	for i, ee := range results {
+		if len(ee.fields) != 3 || ee.fields["goroutine"] != i || ee.fields[fmt.Sprintf("own.%d", i)] != i || ee.fields["shared"] != true {
+			t.Errorf("goroutine %d: got fields %v", i, ee.fields)
+		}
+		if len(ee.args) != 1 || ee.args[0].value != i {
+			t.Errorf("goroutine %d: got args %v", i, ee.args)
+		}
+		if want := fmt.Sprintf("goroutine %d: base", i); ee.Error() != want {
+			t.Errorf("goroutine %d: got message %q, want %q", i, ee.Error(), want)
+		}
+	}
+	if len(base.fields) != 1 || len(base.args) != 0 || base.Error() != "base" || isLogged(base) {
+		t.Errorf("base error modified: %v %v %q", base.fields, base.args, base.Error())
+	}
+}
@@ -0,0 +1,137 @@
+package errors
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Config is a complete configuration built from Options: stack depth and
+// format, key names, payload encodings, policies and sinks. Configs are
+// immutable, With derives a modified copy, so a Config can be shared between
+// goroutines and applied in a single step. A logger can render errors with
+// another Config, see RenderWith:
+//
+//	base := errors.NewConfig(errors.StackDepth(32), errors.Schema(2))
+//	base.Apply()
+//	billing := logger.WithOptions(errors.RenderWith(base.With(errors.PayloadBytes(errors.BytesHex, 64))))
+type Config struct {
+	conf config
+}
+
+// NewConfig returns the default configuration changed by opts.
+func NewConfig(opts ...Option) *Config {
+	c := &Config{conf: defaultConfig}
+	for _, opt := range opts {
+		opt(&c.conf)
+	}
+	return c
+}
+
+// CurrentConfig returns the package configuration in effect.
+func CurrentConfig() *Config {
+	return &Config{conf: *current()}
+}
+
+// With returns a copy of c changed by opts.
+func (c *Config) With(opts ...Option) *Config {
+	derived := &Config{conf: c.conf}
+	for _, opt := range opts {
+		opt(&derived.conf)
+	}
+	return derived
+}
+
+// Keys returns the key names c logs errors with.
+func (c *Config) Keys() Keys {
+	return c.conf.keys
+}
+
+// Apply atomically replaces the package configuration with c, see Configure.
+func (c *Config) Apply() {
+	applied := c.conf
+	settingsMu.Lock()
+	store(&applied)
+	settingsMu.Unlock()
+}
+
+// RenderWith is a zap option rendering the Errors logged by a logger with c
+// instead of the package configuration, to override key names, stack
+// formats or encodings for a module. It applies to Errors logged as objects,
+// see Field; to cover plain error fields too, pass ExpandErrors after it.
+//
+// Only the rendering options of c are used. Errors are created before they
+// reach a logger, so their stack depth, and the Relog policy, stats, audit
+// and sinks of Log, follow the package configuration.
+func RenderWith(c *Config) zap.Option {
+	conf := c.conf
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return configCore{Core: core, conf: &conf}
+	})
+}
+
+type configCore struct {
+	zapcore.Core
+	conf *config
+}
+
+func (c configCore) With(fields []zapcore.Field) zapcore.Core {
+	return configCore{Core: c.Core.With(c.configured(fields)), conf: c.conf}
+}
+
+func (c configCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c configCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return writeChecked(c.Core, entry, c.configured(fields))
+}
+
+// configured replaces the Errors in fields with objects rendered with the
+// configuration of the core.
+func (c configCore) configured(fields []zapcore.Field) []zapcore.Field {
+	var replaced []zapcore.Field
+	for i, field := range fields {
+		if field.Type != zapcore.ObjectMarshalerType {
+			continue
+		}
+		ee, ok := field.Interface.(Error)
+		if !ok {
+			continue
+		}
+		if replaced == nil {
+			replaced = append([]zapcore.Field(nil), fields...)
+		}
+		replaced[i] = zap.Object(field.Key, configuredError{err: ee, conf: c.conf})
+	}
+	if replaced == nil {
+		return fields
+	}
+	return replaced
+}
+
+type configuredError struct {
+	err  Error
+	conf *config
+}
+
+// fieldError returns the Error held by an object field, as built by Field or
+// replaced by RenderWith.
+func fieldError(field zapcore.Field) (Error, bool) {
+	if field.Type != zapcore.ObjectMarshalerType {
+		return Error{}, false
+	}
+	switch e := field.Interface.(type) {
+	case Error:
+		return e, true
+	case configuredError:
+		return e.err, true
+	}
+	return Error{}, false
+}
+
+func (ce configuredError) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	return ce.err.marshal(encoder, ce.conf)
+}
@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+)
+
+func renderingConfig() *Config {
+	keys := CurrentConfig().conf.keys
+	keys.Kind = "category"
+	return NewConfig(SchemaKeys(keys))
+}
+
+func TestRenderWithRedact(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	logger := Redact(zap.New(core), SummaryRedaction).WithOptions(RenderWith(renderingConfig()))
+
+	Log(logger, Errorf("failure").WithKind(KindNotFound).WithPayload(map[string]string{"token": "SECRET"}))
+
+	object := logs.AllUntimed()[0].ContextMap()["error"].(map[string]interface{})
+	if payload, ok := object["payload"]; ok {
+		t.Errorf("payload %v logged by a redacting logger", payload)
+	}
+	if object["category"] != string(KindNotFound) {
+		t.Errorf("got %v, want the kind rendered with the logger configuration", object)
+	}
+}
+
+func TestRenderWithSuppressStacktraces(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(core, zap.AddStacktrace(zap.ErrorLevel), SuppressStacktraces()).
+		WithOptions(RenderWith(renderingConfig()))
+
+	Log(logger, Errorf("failure"))
+
+	if stack := logs.AllUntimed()[0].Stack; stack != "" {
+		t.Errorf("entry stacktrace kept next to the one of the error:\n%s", stack)
+	}
+}
@@ -0,0 +1,70 @@
+// Package configerrors turns the errors of configuration decoders into
+// Errors positioned in the configuration file, for CLI tools reporting
+// invalid configuration with errors.FormatText.
+package configerrors
+
+import (
+	errors "github.com/jpascal/zap-errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var positionPattern = regexp.MustCompile(`line (\d+)(?:, column (\d+)|: column (\d+))?:\s*(.*)`)
+
+// Position is a location in a configuration file, lines and columns
+// starting at 1. A zero column means the whole line.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// At wraps err, found in file at pos. source, the content of the file, may
+// be nil.
+func At(err error, file string, pos Position, source []byte) errors.Error {
+	return at(err, file, pos, source)
+}
+
+// at is At with the stacktrace starting at the caller of the exported
+// function calling it.
+func at(err error, file string, pos Position, source []byte) errors.Error {
+	return errors.WithMessage(err, "%s", file).
+		WithKind(errors.KindInvalid).
+		WithPosition(file, pos.Line, pos.Column, source).
+		WithStacktraceSkip(2)
+}
+
+// FromYAML positions the errors returned by gopkg.in/yaml.v3 when decoding
+// source, read from file, including when they come wrapped by viper. Syntax
+// errors report a single line; type errors, reporting one line per
+// offending value, become an error with one child per value. Errors without
+// positions are wrapped as is.
+func FromYAML(err error, file string, source []byte) error {
+	if err == nil {
+		return nil
+	}
+	var positions []Position
+	var positioned []error
+	for _, line := range strings.Split(err.Error(), "\n") {
+		match := positionPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		pos := Position{}
+		pos.Line, _ = strconv.Atoi(match[1])
+		if column := match[2] + match[3]; column != "" {
+			pos.Column, _ = strconv.Atoi(column)
+		}
+		positions = append(positions, pos)
+		positioned = append(positioned, errors.ErrorfSkip(1, "%s", match[4]).
+			WithKind(errors.KindInvalid).
+			WithPosition(file, pos.Line, pos.Column, source))
+	}
+	switch len(positioned) {
+	case 0:
+		return errors.WithMessage(err, "%s", file).WithKind(errors.KindInvalid).WithStacktraceSkip(1)
+	case 1:
+		return at(err, file, positions[0], source)
+	}
+	return errors.WrapAll(positioned, "%s", file).(errors.Error).WithKind(errors.KindInvalid).WithStacktraceSkip(1)
+}
@@ -0,0 +1,162 @@
+package configerrors
+
+import (
+	"fmt"
+	errors "github.com/jpascal/zap-errors"
+	"gopkg.in/yaml.v3"
+	"strings"
+	"testing"
+)
+
+type settings struct {
+	Port int
+	Name string
+	Tags []string
+}
+
+// decode returns the error of yaml.v3 decoding source into settings.
+func decode(t *testing.T, source string) error {
+	t.Helper()
+	var s settings
+	err := yaml.Unmarshal([]byte(source), &s)
+	if err == nil {
+		t.Fatalf("decoding %q did not fail", source)
+	}
+	return err
+}
+
+func text(err error) string {
+	return errors.FormatText(err, errors.TextOptions{NoStack: true})
+}
+
+func TestFromYAML(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		viper  bool
+		// lines are the lines of the children, or of the error itself when
+		// it has a single position.
+		lines    []int
+		children bool
+	}{
+		{name: "syntax error", source: "port: 80\nname: [a\n", lines: []int{1}},
+		{name: "tab indentation", source: "port: 80\n\tname: x\n", lines: []int{2}},
+		{name: "unexpected end", source: "port: 80\nname: \"x\n", lines: []int{2}},
+		{name: "no line", source: "a: b: c\n"},
+		{name: "type error", source: "port: abc\n", lines: []int{1}},
+		{name: "type errors", source: "port: abc\nname: [1]\ntags: x\n", lines: []int{1, 2, 3}, children: true},
+		{name: "viper syntax error", source: "port: 80\nname: [a\n", viper: true, lines: []int{1}},
+		{name: "viper type errors", source: "port: abc\nname: [1]\n", viper: true, lines: []int{1, 2}, children: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cause := decode(t, tt.source)
+			if tt.viper {
+				cause = fmt.Errorf("While parsing config: %w", cause)
+			}
+			err := FromYAML(cause, "config.yaml", []byte(tt.source))
+
+			ee, ok := err.(errors.Error)
+			if !ok {
+				t.Fatalf("got %T, want an Error", err)
+			}
+			if kind := errors.ToMap(err)["error.kind"]; kind != string(errors.KindInvalid) {
+				t.Errorf("got kind %v, want %q", kind, errors.KindInvalid)
+			}
+			if !tt.children && ee.Unwrap() != cause {
+				t.Errorf("got cause %v, want the yaml error", ee.Unwrap())
+			}
+			children := ee.Errors()
+			if !tt.children {
+				if len(children) != 0 {
+					t.Errorf("got %d children, want none", len(children))
+				}
+				fields := errors.ToMap(err)
+				if len(tt.lines) == 0 {
+					if _, ok := fields["error.config.line"]; ok {
+						t.Errorf("error without a line positioned: %v", fields)
+					}
+					return
+				}
+				if line := fields["error.config.line"]; line != tt.lines[0] {
+					t.Errorf("got line %v, want %d", line, tt.lines[0])
+				}
+				if excerpt := fmt.Sprintf("--> config.yaml:%d\n", tt.lines[0]); !strings.Contains(text(err), excerpt) {
+					t.Errorf("text does not point at %q:\n%s", excerpt, text(err))
+				}
+				return
+			}
+			if len(children) != len(tt.lines) {
+				t.Fatalf("got %d children, want %d", len(children), len(tt.lines))
+			}
+			for i, child := range children {
+				fields := errors.ToMap(child)
+				if line := fields["error.config.line"]; line != tt.lines[i] {
+					t.Errorf("child %d: got line %v, want %d", i, line, tt.lines[i])
+				}
+				if message := fields["error.message"].(string); strings.HasPrefix(message, "line ") || !strings.Contains(message, "cannot unmarshal") {
+					t.Errorf("child %d: got message %q, want the type error without its line", i, message)
+				}
+			}
+		})
+	}
+}
+
+func TestFromYAMLNil(t *testing.T) {
+	if err := FromYAML(nil, "config.yaml", nil); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	source := []byte("server:\n\tport: eighty\n")
+	tests := []struct {
+		name string
+		pos  Position
+		want string
+	}{
+		{
+			name: "caret after tabs",
+			pos:  Position{Line: 2, Column: 8},
+			want: "--> config.yaml:2:8\n" +
+				" 1 | server:\n" +
+				" 2 | \tport: eighty\n" +
+				"   | \t      ^\n",
+		},
+		{
+			name: "column just past the end",
+			pos:  Position{Line: 2, Column: 14},
+			want: "--> config.yaml:2:14\n" +
+				" 1 | server:\n" +
+				" 2 | \tport: eighty\n" +
+				"   | \t            ^\n",
+		},
+		{
+			name: "column beyond the line",
+			pos:  Position{Line: 2, Column: 40},
+			want: "--> config.yaml:2:40\n" +
+				" 1 | server:\n" +
+				" 2 | \tport: eighty\n",
+		},
+		{
+			name: "whole line",
+			pos:  Position{Line: 1},
+			want: "--> config.yaml:1\n" +
+				" 1 | server:\n",
+		},
+		{
+			name: "line beyond the source",
+			pos:  Position{Line: 9, Column: 1},
+			want: "--> config.yaml:9:1\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered := text(At(fmt.Errorf("invalid port"), "config.yaml", tt.pos, source))
+			// Lines after the excerpt belong to the cause.
+			if !strings.Contains(rendered, "  "+strings.ReplaceAll(tt.want, "\n", "\n  ")+"caused by:") {
+				t.Errorf("got:\n%s\nwant the excerpt:\n%s", rendered, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WrapCtx is WithMessage for failures of context-bound operations: it
+// records whether ctx was canceled or its deadline exceeded, the time left
+// until the deadline (negative once passed) and the cancellation cause. The
+// kind is set to KindCanceled or KindTimeout unless already set. The feature
+// flags active for ctx are recorded when a Flags function is configured.
+func WrapCtx(ctx context.Context, err error, format string, a ...interface{}) Error {
+	ee := wrap(err, fmt.Sprintf(format, a...)).WithFlagsFrom(ctx)
+	fields := map[string]interface{}{}
+	if deadline, ok := ctx.Deadline(); ok {
+		fields["ctx.deadline"] = deadline
+		fields["ctx.remaining"] = time.Until(deadline)
+	}
+	switch ctx.Err() {
+	case context.Canceled:
+		fields["ctx.err"] = "canceled"
+		if ee.kind == KindUnknown {
+			ee.kind = KindCanceled
+		}
+	case context.DeadlineExceeded:
+		fields["ctx.err"] = "deadline_exceeded"
+		if ee.kind == KindUnknown {
+			ee.kind = KindTimeout
+		}
+	}
+	if cause := contextCause(ctx); cause != nil && cause != ctx.Err() {
+		fields["ctx.cause"] = cause.Error()
+	}
+	if len(fields) == 0 {
+		return ee
+	}
+	return ee.WithFields(fields)
+}
+
+type errorSlotKey struct{}
+
+// errorSlot lets handlers hand their error to the middlewares wrapping them.
+type errorSlot struct {
+	mu  sync.Mutex
+	err error
+}
+
+// IntoContext attaches err to ctx for FromContext. When a middleware such as
+// httperrors.AccessLog prepared ctx, attaching a nil error, the error is
+// stored where the middleware can pick it up after the handler returns, and
+// ctx itself is returned.
+func IntoContext(ctx context.Context, err error) context.Context {
+	if slot, ok := ctx.Value(errorSlotKey{}).(*errorSlot); ok {
+		slot.mu.Lock()
+		slot.err = err
+		slot.mu.Unlock()
+		return ctx
+	}
+	return context.WithValue(ctx, errorSlotKey{}, &errorSlot{err: err})
+}
+
+// FromContext returns the error attached to ctx by IntoContext.
+func FromContext(ctx context.Context) error {
+	if slot, ok := ctx.Value(errorSlotKey{}).(*errorSlot); ok {
+		slot.mu.Lock()
+		defer slot.mu.Unlock()
+		return slot.err
+	}
+	return nil
+}
+
+// RecordError records the error of the request being handled, for the
+// wrapping middlewares to pick it up, see IntoContext.
+func RecordError(r *http.Request, err error) {
+	IntoContext(r.Context(), err)
+}
@@ -0,0 +1,9 @@
+//go:build !go1.21
+
+package errors
+
+import "context"
+
+func contextCause(ctx context.Context) error {
+	return ctx.Err()
+}
@@ -0,0 +1,119 @@
+// Package core holds the error type of github.com/jpascal/zap-errors without
+// depending on zap, so libraries can return errors carrying a code, a
+// payload and a stacktrace without importing zap. Applications log them with
+// the main package, errors.Field and errors.Log render them like the errors
+// it creates.
+package core
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+)
+
+// stackDepth is the maximum number of frames captured, set by SetStackDepth.
+var stackDepth int32 = 10
+
+// SetStackDepth sets the maximum number of frames captured by Errorf and
+// WithMessage. The main package calls it when configured with StackDepth.
+func SetStackDepth(depth int) {
+	if depth > 0 {
+		atomic.StoreInt32(&stackDepth, int32(depth))
+	}
+}
+
+type Error struct {
+	message string
+	code    int
+	payload interface{}
+	stack   []uintptr
+	err     error
+}
+
+func (e Error) Error() string {
+	return e.message
+}
+
+func (e Error) Unwrap() error {
+	return e.err
+}
+
+// New returns an Error with message wrapping cause, without stacktrace.
+func New(message string, cause error) Error {
+	return Error{message: message, err: cause}
+}
+
+func Errorf(format string, a ...interface{}) Error {
+	return Error{
+		message: fmt.Sprintf(format, a...),
+		stack:   callers(),
+		err:     fmt.Errorf(format, a...),
+	}
+}
+
+// WithMessage wraps err with a message, keeping the code, payload and
+// stacktrace of an Error found in its chain. The message of other errors is
+// not repeated, Unwrap returns them.
+func WithMessage(err error, format string, a ...interface{}) Error {
+	message := fmt.Sprintf(format, a...)
+	var parent Error
+	if errors.As(err, &parent) {
+		parent.message = message + ": " + parent.message
+		return parent
+	}
+	return Error{message: message, stack: callers(), err: err}
+}
+
+// Wrap is like WithMessage but returns nil for a nil err.
+func Wrap(err error, format string, a ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return WithMessage(err, format, a...)
+}
+
+func (e Error) WithCode(code int) Error {
+	e.code = code
+	return e
+}
+
+func (e Error) WithPayload(payload interface{}) Error {
+	e.payload = payload
+	return e
+}
+
+// WithText returns a copy of e with message as its message.
+func (e Error) WithText(message string) Error {
+	e.message = message
+	return e
+}
+
+// WithCause returns a copy of e wrapping err.
+func (e Error) WithCause(err error) Error {
+	e.err = err
+	return e
+}
+
+func (e Error) Message() string {
+	return e.message
+}
+
+func (e Error) Code() int {
+	return e.code
+}
+
+func (e Error) Payload() interface{} {
+	return e.payload
+}
+
+// StackTrace returns the program counters of the stacktrace, to be resolved
+// with runtime.CallersFrames.
+func (e Error) StackTrace() []uintptr {
+	return e.stack
+}
+
+func callers() []uintptr {
+	pc := make([]uintptr, atomic.LoadInt32(&stackDepth))
+	return pc[:runtime.Callers(3, pc)]
+}
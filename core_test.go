@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"fmt"
+	"github.com/jpascal/zap-errors/core"
+	"testing"
+)
+
+func TestCoreWithMessage(t *testing.T) {
+	inner := core.Errorf("connection refused").WithCode(503)
+	wrapped := core.WithMessage(fmt.Errorf("dialing: %w", inner), "fetching")
+	if wrapped.Code() != 503 {
+		t.Errorf("got code %d, want the code of the Error in the chain", wrapped.Code())
+	}
+	if wrapped.Error() != "fetching: connection refused" {
+		t.Errorf("got message %q", wrapped.Error())
+	}
+
+	cause := fmt.Errorf("connection refused")
+	foreign := core.WithMessage(cause, "fetching")
+	if foreign.Error() != "fetching" {
+		t.Errorf("got message %q, the message of the cause is repeated", foreign.Error())
+	}
+	if foreign.Unwrap() != cause {
+		t.Errorf("got cause %v, want %v", foreign.Unwrap(), cause)
+	}
+}
+
+func TestCoreStackDepth(t *testing.T) {
+	defer CurrentConfig().Apply()
+	Configure(StackDepth(2))
+	if stack := core.Errorf("failure").StackTrace(); len(stack) != 2 {
+		t.Errorf("got %d frames, want 2", len(stack))
+	}
+	Configure(StackDepth(32))
+	if stack := core.Errorf("failure").StackTrace(); len(stack) <= 2 {
+		t.Errorf("got %d frames, want the whole stack", len(stack))
+	}
+}
+
+func TestCoreRendersLikeError(t *testing.T) {
+	ce := core.New("failure", nil).WithCode(42).WithPayload("details")
+	ee, ok := errorOf(ce)
+	if !ok {
+		t.Fatal("core.Error is not converted")
+	}
+	if ee.Error() != ce.Error() || ee.base.Code() != 42 || ee.base.Payload() != "details" {
+		t.Errorf("got %q %d %v", ee.Error(), ee.base.Code(), ee.base.Payload())
+	}
+}
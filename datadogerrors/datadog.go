@@ -0,0 +1,50 @@
+// Package datadogerrors logs the errors of github.com/jpascal/zap-errors the
+// way Datadog Error Tracking and log-trace correlation expect them.
+package datadogerrors
+
+import (
+	"context"
+	errors "github.com/jpascal/zap-errors"
+	"go.uber.org/zap"
+	"strconv"
+)
+
+// Keys names the logged error fields the way Datadog Error Tracking ingests
+// them from logs: error.message, error.kind holding the type of the cause
+// and error.stack. The kind of errors moves to error.category.
+//
+//	errors.Configure(errors.SchemaKeys(datadogerrors.Keys))
+var Keys = errors.Keys{
+	Kind:       "category",
+	Type:       "kind",
+	Stacktrace: "stack",
+}
+
+// SpanFunc returns the ids of the span active in ctx, typically:
+//
+//	func(ctx context.Context) (uint64, uint64, bool) {
+//		span, ok := tracer.SpanFromContext(ctx)
+//		if !ok {
+//			return 0, 0, false
+//		}
+//		return span.Context().TraceID(), span.Context().SpanID(), true
+//	}
+type SpanFunc func(ctx context.Context) (traceID, spanID uint64, ok bool)
+
+// Trace returns the dd.trace_id and dd.span_id fields correlating logs with
+// the span active in ctx, or no fields without one:
+//
+//	errors.Log(logger.With(spans.Trace(ctx)...), err)
+func (spans SpanFunc) Trace(ctx context.Context) []zap.Field {
+	if spans == nil {
+		return nil
+	}
+	traceID, spanID, ok := spans(ctx)
+	if !ok {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("dd.trace_id", strconv.FormatUint(traceID, 10)),
+		zap.String("dd.span_id", strconv.FormatUint(spanID, 10)),
+	}
+}
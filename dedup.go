@@ -0,0 +1,119 @@
+package errors
+
+import (
+	"container/list"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sync"
+	"time"
+)
+
+// Deduper collapses identical errors logged within a time window into a
+// single entry carrying the number of occurrences. Entries are written when
+// the window of an error closes, when it is evicted to make room for newer
+// errors, or on Flush. Errors are written right away while deduplication is
+// disabled with DiagnosticsHandler.
+type Deduper struct {
+	logger  *zap.Logger
+	window  time.Duration
+	size    int
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type dedupEntry struct {
+	fingerprint string
+	err         error
+	fields      []zapcore.Field
+	occurrences int
+	timer       *time.Timer
+}
+
+// NewDeduper creates a Deduper remembering at most size distinct errors.
+func NewDeduper(logger *zap.Logger, window time.Duration, size int) *Deduper {
+	if size < 1 {
+		size = 1
+	}
+	return &Deduper{
+		logger:  logger,
+		window:  window,
+		size:    size,
+		entries: make(map[string]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+// Log writes err once per window. The first occurrence is held back, not
+// written, until its window closes, it is evicted by newer errors or Flush
+// runs; the entry then carries the number of occurrences seen meanwhile.
+func (d *Deduper) Log(err error) {
+	d.log(err)
+}
+
+// log is Log adding fields to the entry, those of the first occurrence when
+// errors are collapsed.
+func (d *Deduper) log(err error, fields ...zapcore.Field) {
+	if err == nil {
+		return
+	}
+	if !dedupEnabled() {
+		d.write(&dedupEntry{err: err, fields: fields, occurrences: 1})
+		return
+	}
+	fingerprint := Fingerprint(err)
+	var evicted *dedupEntry
+	d.mu.Lock()
+	if element, ok := d.entries[fingerprint]; ok {
+		element.Value.(*dedupEntry).occurrences++
+		d.mu.Unlock()
+		return
+	}
+	if d.order.Len() >= d.size {
+		evicted = d.remove(d.order.Front())
+	}
+	entry := &dedupEntry{fingerprint: fingerprint, err: err, fields: fields, occurrences: 1}
+	element := d.order.PushBack(entry)
+	d.entries[fingerprint] = element
+	entry.timer = time.AfterFunc(d.window, func() {
+		d.mu.Lock()
+		if current, ok := d.entries[fingerprint]; !ok || current != element {
+			d.mu.Unlock()
+			return
+		}
+		d.remove(element)
+		d.mu.Unlock()
+		d.write(entry)
+	})
+	d.mu.Unlock()
+	if evicted != nil {
+		d.write(evicted)
+	}
+}
+
+// Flush writes all pending errors immediately.
+func (d *Deduper) Flush() {
+	d.mu.Lock()
+	pending := make([]*dedupEntry, 0, d.order.Len())
+	for d.order.Len() > 0 {
+		pending = append(pending, d.remove(d.order.Front()))
+	}
+	d.mu.Unlock()
+	for _, entry := range pending {
+		d.write(entry)
+	}
+}
+
+func (d *Deduper) remove(element *list.Element) *dedupEntry {
+	entry := d.order.Remove(element).(*dedupEntry)
+	delete(d.entries, entry.fingerprint)
+	entry.timer.Stop()
+	return entry
+}
+
+// write logs entry like Log, at the level of the error and following the
+// Relog policy, with its fields and the number of occurrences.
+func (d *Deduper) write(entry *dedupEntry) {
+	fields := append(entry.fields[:len(entry.fields):len(entry.fields)], zap.Int("occurrences", entry.occurrences))
+	logFields(entry.err, fields, d.logger)
+}
@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+	"time"
+)
+
+func TestDeduperLogsLikeLog(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	deduper := NewDeduper(zap.New(core), time.Hour, 8)
+	err := Warningf("cache miss")
+	for i := 0; i < 3; i++ {
+		deduper.Log(err)
+	}
+	deduper.Flush()
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if entries[0].Level != zapcore.WarnLevel {
+		t.Errorf("got level %v, want the level of the error", entries[0].Level)
+	}
+	if occurrences := entries[0].ContextMap()["occurrences"]; occurrences != int64(3) {
+		t.Errorf("got %v occurrences, want 3", occurrences)
+	}
+	if !isLogged(err) {
+		t.Error("the error is not marked as logged")
+	}
+}
@@ -0,0 +1,29 @@
+package errors
+
+// WithDependency records the downstream dependency that failed as the
+// dependency.name and dependency.endpoint fields, the endpoint being left out
+// when empty. Errors carrying a dependency are classified as dependency
+// failures unless they are timeouts, see Classify.
+func (ee Error) WithDependency(name, endpoint string) Error {
+	fields := map[string]interface{}{"dependency.name": name}
+	if endpoint != "" {
+		fields["dependency.endpoint"] = endpoint
+	}
+	return ee.WithFields(fields)
+}
+
+func hasDependency(err error) bool {
+	_, ok := DependencyOf(err)
+	return ok
+}
+
+// DependencyOf returns the name of the dependency recorded with
+// WithDependency in the chain of err.
+func DependencyOf(err error) (string, bool) {
+	var name string
+	found := anyError(err, func(ee Error) bool {
+		name, _ = ee.fields["dependency.name"].(string)
+		return name != ""
+	})
+	return name, found
+}
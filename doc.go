@@ -0,0 +1,19 @@
+// Package errors creates errors carrying a code, a kind, a payload and a
+// stacktrace, and logs them with zap as structured objects.
+//
+// The module is laid out by dependencies, integrations living in their own
+// package so that importing one only pulls what it needs:
+//
+//   - core holds a zap-free error type for libraries that want to return rich
+//     errors without importing zap; the errors it creates are rendered by
+//     this package like its own.
+//   - errors, this package, creates, classifies and logs errors with zap.
+//   - httperrors builds errors from failed HTTP responses and transports, and
+//     writes error responses and access logs.
+//   - otlperrors exports errors as OpenTelemetry log records.
+//   - datadogerrors names fields and correlates logs for Datadog.
+//   - configerrors positions the errors of configuration decoders.
+//
+// The integrations import this package, which cannot re-export them without
+// an import cycle.
+package errors
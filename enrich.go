@@ -0,0 +1,39 @@
+package errors
+
+import "github.com/jpascal/zap-errors/core"
+
+// causeEnrichers lift details of well-known error types found in the chain of
+// a wrapped foreign error into structured fields and a kind.
+var causeEnrichers []func(ee Error, cause error) Error
+
+func enrichCause(ee Error, cause error) Error {
+	for _, enricher := range causeEnrichers {
+		ee = enricher(ee, cause)
+	}
+	return ee
+}
+
+// setKind sets the kind unless the error already has one.
+func (ee Error) setKind(kind Kind) Error {
+	if ee.kind == KindUnknown {
+		ee.kind = kind
+	}
+	return ee
+}
+
+// promote returns err itself when it is an Error, or an Error wrapping it
+// without capturing a stacktrace. The stacktrace of an Error found in the
+// chain of err is carried forward, as the wrapper is rendered in its place.
+func promote(err error) Error {
+	if ee, ok := err.(Error); ok {
+		return ee
+	}
+	promoted := Error{base: core.New(err.Error(), err), mark: newMark()}
+	var inner Error
+	if asError(err, &inner) {
+		promoted.stacktrace = inner.stacktrace
+		promoted.recoveredAt = inner.recoveredAt
+		promoted.goroutine = inner.goroutine
+	}
+	return promoted
+}
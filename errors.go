@@ -4,40 +4,166 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"github.com/jpascal/zap-errors/core"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"runtime"
+	"time"
 )
 
+// Error values are immutable: With* methods and wrapping return copies that
+// share no mutable state with the original, so goroutines can enrich the same
+// base error concurrently. Payloads are shared between copies and must not be
+// modified once attached.
 type Error struct {
-	message    string
-	payload    interface{}
-	code       int
-	stacktrace []*runtime.Frame
-	err        error
+	base            core.Error
+	kind            Kind
+	tenant          string
+	user            string
+	severity        zapcore.Level
+	hasSeverity     bool
+	warning         bool
+	audit           bool
+	stacktrace      []*runtime.Frame
+	children        []child
+	retry           retryability
+	fields          map[string]interface{}
+	mark            *logMark
+	panicked        bool
+	panicValue      interface{}
+	goroutine       string
+	recoveredAt     []*runtime.Frame
+	position        *position
+	args            []frameArg
+	chain           []string
+	groupID         string
+	cachedAt        time.Time
+	expiresAt       time.Time
+	flight          flightRole
+	payloadRedacted bool
+	pooled          bool
 }
 
 func (ee Error) Error() string {
-	return ee.message
+	return ee.base.Message()
 }
 
 func (ee Error) Unwrap() error {
-	return ee.err
+	return ee.base.Unwrap()
 }
 
 func (ee Error) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
-	if len(ee.message) > 0 {
-		encoder.AddString("message", ee.err.Error())
+	return ee.marshal(encoder, current())
+}
+
+// marshal renders ee with the configuration conf, the package configuration
+// or the one set on a logger with RenderWith.
+func (ee Error) marshal(encoder zapcore.ObjectEncoder, conf *config) error {
+	keys := conf.keys
+	// Fields added after version 2 of the layout are logged from version 3.
+	extended := conf.schema >= 3
+	// ee is boxed once for the helpers taking an error, each conversion
+	// copying the whole Error.
+	var err error = ee
+	if conf.schema >= 2 {
+		encoder.AddInt(keys.Schema, conf.schema)
 	}
-	if len(ee.stacktrace) > 0 {
-		buffer := bytes.NewBuffer([]byte{})
-		for _, frame := range ee.stacktrace {
-			_, _ = fmt.Fprintf(buffer, "%s\t\n%s:%d\n", frame.Function, frame.File, frame.Line)
+	if message := ee.base.Message(); len(message) > 0 {
+		if cause := ee.base.Unwrap(); cause != nil {
+			encoder.AddString(keys.Message, cause.Error())
+		} else {
+			encoder.AddString(keys.Message, message)
 		}
-		encoder.AddString("stacktrace", buffer.String())
 	}
-	if ee.payload != nil {
-		if err := encoder.AddReflected("payload", ee.payload); err != nil {
+	if chain := ee.chain; extended && len(chain) > 0 {
+		if err := encoder.AddArray(keys.Chain, zapcore.ArrayMarshalerFunc(func(encoder zapcore.ArrayEncoder) error {
+			for _, message := range chain {
+				encoder.AppendString(message)
+			}
+			return nil
+		})); err != nil {
+			return err
+		}
+	}
+	if code := resolveCode(err, conf); conf.schema >= 2 && code != 0 {
+		encoder.AddInt(keys.Code, code)
+		if subsystem, ok := SubsystemOf(code); extended && ok {
+			encoder.AddString(keys.Subsystem, subsystem)
+		}
+	}
+	if ee.kind != KindUnknown {
+		encoder.AddString(keys.Kind, string(ee.kind))
+	}
+	if extended && ee.tenant != "" {
+		encoder.AddString(keys.Tenant, conf.identities.render(ee.tenant))
+	}
+	if extended && ee.user != "" {
+		encoder.AddString(keys.User, conf.identities.render(ee.user))
+	}
+	if extended && ee.panicked {
+		encoder.AddString(keys.Origin, "panic")
+		if ee.goroutine != "" {
+			encoder.AddString(keys.Goroutine, ee.goroutine)
+		}
+	}
+	if extended {
+		encoder.AddString(keys.Class, Classify(err))
+	}
+	if extended && ee.groupID != "" {
+		encoder.AddString(keys.GroupID, ee.groupID)
+	}
+	if extended && !ee.cachedAt.IsZero() {
+		encoder.AddBool(keys.Cached, true)
+		encoder.AddDuration(keys.Age, time.Since(ee.cachedAt))
+	}
+	if extended {
+		if name := causeType(ee.base.Unwrap()); name != "" {
+			encoder.AddString(keys.Type, name)
+		}
+	}
+	if extended && ee.retry != retryUnknown {
+		encoder.AddBool(keys.Retryable, ee.retry == retryYes)
+	}
+	if extended && ee.flight != flightNone {
+		encoder.AddBool(keys.Shared, true)
+		encoder.AddString(keys.Leader, Fingerprint(err))
+	}
+	if len(ee.stacktrace) > 0 && ee.flight != flightFollower {
+		if conf.rawPCs != PCsOnly {
+			buffer := bufferPool.Get().(*bytes.Buffer)
+			writeStack(buffer, ee.stacktrace, ee.args, conf)
+			encoder.AddString(keys.Stacktrace, buffer.String())
+			buffer.Reset()
+			bufferPool.Put(buffer)
+		}
+		if extended && len(ee.recoveredAt) > 0 && conf.rawPCs != PCsOnly {
+			buffer := bufferPool.Get().(*bytes.Buffer)
+			writeStack(buffer, ee.recoveredAt, nil, conf)
+			encoder.AddString(keys.RecoveredAt, buffer.String())
+			buffer.Reset()
+			bufferPool.Put(buffer)
+		}
+		if extended && conf.rawPCs != PCsOff {
+			if err := encoder.AddArray(keys.PCs, pcOffsets(ee.stacktrace)); err != nil {
+				return err
+			}
+		}
+		if extended && conf.components != nil {
+			if component, ok := conf.components.component(ee.stacktrace); ok {
+				encoder.AddString(keys.Component, component)
+			}
+		}
+	}
+	if err := addFields(encoder, ee.fields); err != nil {
+		return err
+	}
+	if payload := resolvePayload(err, conf); payload != nil && payloadsEnabled() && !ee.payloadRedacted {
+		if err := addPayload(encoder, payload, conf); err != nil {
+			return err
+		}
+	}
+	if extended && len(ee.children) > 0 {
+		if err := encoder.AddArray(keys.Errors, marshalChildren(ee.children, conf)); err != nil {
 			return err
 		}
 	}
@@ -45,37 +171,80 @@ func (ee Error) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
 }
 
 func Errorf(format string, a ...interface{}) Error {
+	return errorf(zapcore.ErrorLevel, 1, format, a...)
+}
+
+// ErrorfSkip is like Errorf but leaves skip additional frames out of the
+// stacktrace, so error helpers can exclude themselves: a skip of 1 makes the
+// trace start at the caller of the function calling ErrorfSkip.
+func ErrorfSkip(skip int, format string, a ...interface{}) Error {
+	return errorf(zapcore.ErrorLevel, 1+skip, format, a...)
+}
+
+func errorf(level zapcore.Level, skip int, format string, a ...interface{}) Error {
+	statsCreated()
 	return Error{
-		err:        fmt.Errorf(format, a...),
-		stacktrace: stackTrace(),
-		message:    fmt.Sprintf(format, a...),
+		base:       core.New(fmt.Sprintf(format, a...), fmt.Errorf(format, a...)),
+		stacktrace: stackTraceAt(level, skip),
+		mark:       newMark(),
 	}
 }
 
 func WithMessage(err error, format string, a ...interface{}) Error {
-	var parentEnhancedError Error
-	if errors.As(err, &parentEnhancedError) {
-		if parentEnhancedError.stacktrace == nil {
-			parentEnhancedError.stacktrace = stackTrace()
+	return wrap(err, fmt.Sprintf(format, a...))
+}
+
+func wrap(err error, message string) Error {
+	if err == nil && !current().wrapNil {
+		misuse(nil, "wrapping a nil error")
+	}
+	parentEnhancedError, ok := err.(Error)
+	if !ok && err != nil {
+		parentEnhancedError, ok = findError(err)
+	}
+	if ok {
+		if parentEnhancedError.stacktrace == nil && parentEnhancedError.wantsStack() {
+			parentEnhancedError.stacktrace = stackTraceAt(parentEnhancedError.level(), 1)
 		}
-		parentEnhancedError.message = fmt.Sprintf(format, a...) + ": " + parentEnhancedError.message
-		return parentEnhancedError
+		return parentEnhancedError.own().withContext(message)
 	}
-	return Error{
-		err:        err,
-		stacktrace: stackTrace(),
-		message:    fmt.Sprintf(format, a...),
+	statsCreated()
+	mark := newMark()
+	if err != nil && isLogged(err) {
+		mark.logged = 1
+	}
+	return enrichCause(Error{
+		base:       core.New(message, err),
+		stacktrace: stackTraceAt(zapcore.ErrorLevel, 1),
+		mark:       mark,
+	}, err)
+}
+
+// Wrap is like WithMessage but returns nil for a nil err, unless the WrapNil
+// option is enabled.
+func Wrap(err error, format string, a ...interface{}) error {
+	if err == nil && !current().wrapNil {
+		return nil
 	}
+	return wrap(err, fmt.Sprintf(format, a...))
 }
 
 func (ee Error) WithPayload(payload interface{}) Error {
-	ee.payload = payload
-	return ee
+	ee.base = ee.base.WithPayload(payload)
+	return ee.enriched()
 }
 
 func (ee Error) WithCode(code int) Error {
-	ee.code = code
-	return ee
+	ee.base = ee.base.WithCode(code)
+	return ee.enriched()
+}
+
+// HasCode reports whether an Error anywhere in the chain of err, joined
+// children included, carries code.
+func HasCode(err error, code int) bool {
+	return anyError(err, func(ee Error) bool {
+		return ee.base.Code() == code
+	})
 }
 
 func (ee Error) WithStacktrace() Error {
@@ -83,25 +252,112 @@ func (ee Error) WithStacktrace() Error {
 	return ee
 }
 
+// WithStacktraceSkip is like WithStacktrace but leaves skip additional frames
+// out of the stacktrace, see ErrorfSkip.
+func (ee Error) WithStacktraceSkip(skip int) Error {
+	ee.stacktrace = stackTraceSkip(skip)
+	return ee
+}
+
+func (ee Error) enriched() Error {
+	ee = ee.own()
+	if current().stackOnEnrich && len(ee.stacktrace) == 0 && ee.wantsStack() {
+		ee.stacktrace = stackTraceAt(ee.level(), 1)
+	}
+	return ee
+}
+
+// findError is errors.As for an Error target. It is kept out of wrap, whose
+// Error would otherwise escape to the heap even when err is an Error.
+func findError(err error) (Error, bool) {
+	var ee Error
+	found := errors.As(err, &ee)
+	return ee, found
+}
+
+// own copies the frame slice of pooled Errors, which is reused once they are
+// released, see Acquire. Other Errors never modify their frame slice in
+// place and share it. The copy gets its own log mark, see logMark.derive.
+func (ee Error) own() Error {
+	if ee.pooled {
+		ee.stacktrace = append([]*runtime.Frame(nil), ee.stacktrace...)
+		ee.pooled = false
+	}
+	ee.mark = ee.mark.derive()
+	return ee
+}
+
+func EnsureStack(err error) error {
+	return ensureStack(err, 1)
+}
+
+func ensureStack(err error, skip int) error {
+	if err == nil {
+		return nil
+	}
+	var ee Error
+	if asError(err, &ee) && len(ee.stacktrace) > 0 {
+		return err
+	}
+	if ee, ok := err.(Error); ok {
+		ee.stacktrace = stackTraceSkip(skip)
+		return ee
+	}
+	return enrichCause(Error{
+		base:       core.New(err.Error(), err),
+		stacktrace: stackTraceSkip(skip),
+		mark:       newMark(),
+	}, err)
+}
+
 func stackTrace() []*runtime.Frame {
-	pc := make([]uintptr, 10)
-	n := runtime.Callers(0, pc)
-	pc = pc[3:n]
-	frames := runtime.CallersFrames(pc)
-	traceFrames := make([]*runtime.Frame, 0)
-	for {
-		frame, more := frames.Next()
-		if !more {
-			break
-		}
-		traceFrames = append(traceFrames, &frame)
+	return stackTraceSkip(1)
+}
+
+func stackTraceSkip(skip int) []*runtime.Frame {
+	return appendStackTrace(nil, skip+1)
+}
+
+func stackTraceAt(level zapcore.Level, skip int) []*runtime.Frame {
+	if !stackEnabled(level) {
+		return nil
+	}
+	return appendStackTrace(nil, skip+1)
+}
+
+func appendStackTrace(traceFrames []*runtime.Frame, skip int) []*runtime.Frame {
+	var buffer [32]uintptr
+	pc := buffer[:]
+	if depth := current().stackDepth; depth <= len(buffer) {
+		pc = buffer[:depth]
+	} else {
+		pc = make([]uintptr, depth)
+	}
+	n := runtime.Callers(3+skip, pc)
+	return appendFrames(traceFrames, pc[:n])
+}
+
+// appendFrames resolves pcs as returned by runtime.Callers, leaving out the
+// last frame.
+func appendFrames(traceFrames []*runtime.Frame, pcs []uintptr) []*runtime.Frame {
+	if traceFrames == nil {
+		traceFrames = make([]*runtime.Frame, 0, len(pcs))
+	}
+	for _, pc := range pcs {
+		traceFrames = append(traceFrames, frameCache.frames(pc)...)
+	}
+	if len(traceFrames) > 0 {
+		traceFrames = traceFrames[:len(traceFrames)-1]
 	}
 	return traceFrames
 }
 
 func Field(err error) zap.Field {
+	if marshaler, ok := objectOf(err); ok {
+		return zap.Object("error", marshaler)
+	}
 	var ee Error
-	if errors.As(err, &ee) {
+	if asError(err, &ee) {
 		return zap.Object("error", ee)
 	} else if err != nil {
 		return zap.Any("error", map[string]string{"message": err.Error()})
@@ -109,6 +365,31 @@ func Field(err error) zap.Field {
 	return zap.Skip()
 }
 
+// Zap is a drop-in replacement for zap.Error: plain errors are logged
+// exactly like zap.Error does, as a string under the error key, while Errors
+// are expanded like Field does. Call sites can be migrated with:
+//
+//	gofmt -r 'zap.Error(a) -> errors.Zap(a)'
+func Zap(err error) zap.Field {
+	if marshaler, ok := objectOf(err); ok {
+		return zap.Object("error", marshaler)
+	}
+	var ee Error
+	if asError(err, &ee) {
+		return zap.Object("error", ee)
+	}
+	return zap.Error(err)
+}
+
+// objectOf returns err as an ObjectMarshaler when it is an Error, reusing the
+// copy err already holds instead of boxing another one.
+func objectOf(err error) (zapcore.ObjectMarshaler, bool) {
+	if _, ok := err.(Error); !ok {
+		return nil, false
+	}
+	return err.(zapcore.ObjectMarshaler), true
+}
+
 func As(err error, target interface{}) bool {
 	return errors.As(err, &target)
 }
@@ -121,5 +402,91 @@ func Unwrap(err error) error {
 }
 
 func Log(logger *zap.Logger, err error) {
-	logger.With(Field(err)).Error(err.Error())
+	logTo(err, logger)
+}
+
+func logTo(err error, loggers ...*zap.Logger) {
+	logFields(err, nil, loggers...)
+}
+
+// logFields is logTo adding fields to the entries after the error.
+func logFields(err error, fields []zapcore.Field, loggers ...*zap.Logger) {
+	if err == nil {
+		return
+	}
+	logAt(levelOf(err), err, fields, loggers...)
+}
+
+// logAt is logFields at level rather than the level of err.
+func logAt(level zapcore.Level, err error, fields []zapcore.Field, loggers ...*zap.Logger) {
+	level, ok := relogAt(level, err)
+	if !ok {
+		return
+	}
+	if current().strict {
+		defer enterLog(loggers)()
+	}
+	statsLogged(err)
+	for _, logger := range loggers {
+		if logger == nil {
+			misuse(nil, "logging to a nil logger")
+			continue
+		}
+		if entry := logger.Check(level, err.Error()); entry != nil {
+			entry.Write(append([]zapcore.Field{Field(err)}, fields...)...)
+		}
+	}
+	setLogged(err)
+	audit(err)
+	recordRecent(err)
+}
+
+// LogWith logs err through write rather than a zap logger, for integrations
+// exporting errors elsewhere: write is called with the level of err unless
+// the Relog policy drops it, and err is counted, marked as logged, audited
+// and kept by Recent like Log does.
+func LogWith(err error, write func(level zapcore.Level) error) error {
+	if err == nil {
+		return nil
+	}
+	level, ok := relogLevel(err)
+	if !ok {
+		return nil
+	}
+	statsLogged(err)
+	writeErr := write(level)
+	setLogged(err)
+	audit(err)
+	recordRecent(err)
+	return writeErr
+}
+
+// CheckedLog logs err at level for hot paths: when level is disabled it
+// returns before any work on err, such as building the error object or
+// marking it as logged. Unlike Log the level is given rather than derived
+// from err. Repeated logs follow the Relog policy.
+func CheckedLog(logger *zap.Logger, level zapcore.Level, err error) {
+	if err == nil || logger != nil && !logger.Core().Enabled(level) {
+		return
+	}
+	logAt(level, err, nil, logger)
+}
+
+// relogLevel returns the level err is logged at, or false when the Relog
+// policy drops it.
+func relogLevel(err error) (zapcore.Level, bool) {
+	return relogAt(levelOf(err), err)
+}
+
+// relogAt is relogLevel for err logged at level.
+func relogAt(level zapcore.Level, err error) (zapcore.Level, bool) {
+	if isLogged(err) {
+		switch current().relog {
+		case RelogSkip:
+			return level, false
+		case RelogDebug:
+			level = zapcore.DebugLevel
+		}
+	}
+	return level, true
 }
@@ -0,0 +1,147 @@
+package errors
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+var benchEncoder = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+
+func deepChain(depth int) Error {
+	ee := Errorf("root failure")
+	for i := 0; i < depth; i++ {
+		ee = WithMessage(ee, "layer")
+	}
+	return ee
+}
+
+func encode(tb testing.TB, err error) {
+	buffer, encodeErr := benchEncoder.EncodeEntry(zapcore.Entry{Message: "failure"}, []zapcore.Field{Field(err)})
+	if encodeErr != nil {
+		tb.Fatal(encodeErr)
+	}
+	buffer.Free()
+}
+
+func BenchmarkErrorf(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Errorf("failure %d", i)
+	}
+}
+
+func BenchmarkWithMessageChain(b *testing.B) {
+	for _, depth := range []int{1, 10, 50} {
+		base := Errorf("root failure")
+		b.Run(strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ee := base
+				for j := 0; j < depth; j++ {
+					ee = WithMessage(ee, "layer")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkField(b *testing.B) {
+	ee := Errorf("failure")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Field(ee)
+	}
+}
+
+func BenchmarkMarshalLogObject(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		err  Error
+	}{
+		{"plain", Errorf("failure")},
+		{"payload", Errorf("failure").WithPayload(map[string]int{"id": 1})},
+		{"chain", deepChain(10)},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				encode(b, bench.err)
+			}
+		})
+	}
+}
+
+// budget is the maximum number of allocations and of bytes allocated per
+// call.
+type budget struct {
+	allocs float64
+	bytes  uint64
+}
+
+// allocationBudgets are the budgets of the hot path, enforced by
+// TestAllocationBudgets and measured with the benchmarks above: creating and
+// wrapping errors, building the field and encoding the error object to JSON
+// with and without a payload or a message chain. An Error is 400 bytes, 416
+// once boxed, so every copy of one into an interface shows in the bytes.
+// Raising a budget needs a reason in the commit message.
+var allocationBudgets = map[string]budget{
+	// The error made by fmt.Errorf and its message, the stacktrace and the
+	// log mark.
+	"Errorf": {allocs: 4, bytes: 128},
+	// The copy of the Error boxed into the error argument, the formatted
+	// message, the message joined to the one of the cause and the derived
+	// log mark.
+	"WithMessage": {allocs: 4, bytes: 512},
+	// The copy boxed into the error argument only, Field reuses it.
+	"Field": {allocs: 1, bytes: 416},
+	// The copy boxed into the error argument, the one marshal boxes once for
+	// the resolvers, the fields of the entry, the buffer writing the frames
+	// and the stacktrace string.
+	"MarshalLogObject": {allocs: 5, bytes: 1536},
+	// MarshalLogObject and encoding/json encoding the payload map.
+	"MarshalLogObject/payload": {allocs: 10, bytes: 1792},
+	// Like MarshalLogObject, the layers are joined into the message when
+	// wrapping.
+	"MarshalLogObject/chain": {allocs: 5, bytes: 1536},
+}
+
+// bytesPerRun is testing.AllocsPerRun for the bytes allocated by fn.
+func bytesPerRun(runs int, fn func()) uint64 {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+	fn()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for i := 0; i < runs; i++ {
+		fn()
+	}
+	runtime.ReadMemStats(&after)
+	return (after.TotalAlloc - before.TotalAlloc) / uint64(runs)
+}
+
+func TestAllocationBudgets(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are not representative under the race detector")
+	}
+	plain := Errorf("failure")
+	withPayload := Errorf("failure").WithPayload(map[string]int{"id": 1})
+	chain := deepChain(10)
+	for name, fn := range map[string]func(){
+		"Errorf":                   func() { _ = Errorf("failure") },
+		"WithMessage":              func() { _ = WithMessage(plain, "layer") },
+		"Field":                    func() { _ = Field(plain) },
+		"MarshalLogObject":         func() { encode(t, plain) },
+		"MarshalLogObject/payload": func() { encode(t, withPayload) },
+		"MarshalLogObject/chain":   func() { encode(t, chain) },
+	} {
+		limit := allocationBudgets[name]
+		if allocs := testing.AllocsPerRun(100, fn); allocs > limit.allocs {
+			t.Errorf("%s allocates %v times, budget is %v", name, allocs, limit.allocs)
+		}
+		if bytes := bytesPerRun(100, fn); bytes > limit.bytes {
+			t.Errorf("%s allocates %d bytes, budget is %d", name, bytes, limit.bytes)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package errors
+
+// EventPrefix sets the prefix of the keys returned by ToMap, "error." by
+// default.
+func EventPrefix(prefix string) Option {
+	return func(c *config) {
+		c.eventPrefix = prefix
+	}
+}
+
+// ToMap renders err as a flat map ready to be added to the events of tools
+// like Honeycomb's libhoney: the fields of the logged error object are
+// prefixed, nested objects having their keys joined with dots. It returns nil
+// for a nil error.
+func ToMap(err error) map[string]interface{} {
+	if err == nil {
+		return nil
+	}
+	event := make(map[string]interface{})
+	flatten(current().eventPrefix, ZerologDict(err), event)
+	return event
+}
+
+// flatten copies object into flat, nested objects having their keys joined
+// with dots.
+func flatten(prefix string, object map[string]interface{}, flat map[string]interface{}) {
+	for key, value := range object {
+		if nested, ok := value.(map[string]interface{}); ok {
+			flatten(prefix+key+".", nested, flat)
+			continue
+		}
+		flat[prefix+key] = value
+	}
+}
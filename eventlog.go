@@ -0,0 +1,65 @@
+package errors
+
+import "go.uber.org/zap/zapcore"
+
+// EventType is the type of a Windows Event Log entry.
+type EventType int
+
+const (
+	EventError EventType = iota
+	EventWarning
+	EventInformation
+)
+
+// EventLogEntry holds what a Windows Event Log entry needs, ready to be
+// reported with golang.org/x/sys/windows/svc/eventlog:
+//
+//	e := errors.ToEventLog(err, errors.TextOptions{ShortenPackages: true})
+//	if e.Type == errors.EventError {
+//		_ = elog.Error(e.EventID, e.Description)
+//	}
+type EventLogEntry struct {
+	Type EventType
+	// EventID is the code of the error, or 1 when it has none or it does
+	// not fit the 16 bits of event IDs.
+	EventID uint32
+	// Category is the position of the kind among the Kind constants, 0 for
+	// unknown kinds.
+	Category    uint16
+	Description string
+}
+
+var eventCategories = map[Kind]uint16{
+	KindInvalid:          1,
+	KindNotFound:         2,
+	KindConflict:         3,
+	KindUnauthenticated:  4,
+	KindPermissionDenied: 5,
+	KindCanceled:         6,
+	KindTimeout:          7,
+	KindUnavailable:      8,
+	KindInternal:         9,
+	KindTLS:              10,
+}
+
+// ToEventLog converts err, the description being rendered by FormatText.
+func ToEventLog(err error, opts TextOptions) EventLogEntry {
+	entry := EventLogEntry{EventID: 1, Description: FormatText(err, opts)}
+	switch level := levelOf(err); {
+	case level >= zapcore.ErrorLevel:
+		entry.Type = EventError
+	case level == zapcore.WarnLevel:
+		entry.Type = EventWarning
+	default:
+		entry.Type = EventInformation
+	}
+	var ee Error
+	if !asError(err, &ee) {
+		return entry
+	}
+	if code := resolveCode(err, current()); code > 0 && code <= 0xFFFF {
+		entry.EventID = uint32(code)
+	}
+	entry.Category = eventCategories[ee.kind]
+	return entry
+}
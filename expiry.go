@@ -0,0 +1,18 @@
+package errors
+
+import "time"
+
+// WithExpiry marks the error as a cached negative result valid until t.
+// Logged errors carrying an expiry are annotated with cached: true and the
+// age of the cache entry, so they are not mistaken for fresh failures.
+func (ee Error) WithExpiry(t time.Time) Error {
+	ee.cachedAt = time.Now()
+	ee.expiresAt = t
+	return ee.enriched()
+}
+
+// IsStale reports whether err carries an expiry that has passed.
+func IsStale(err error) bool {
+	var ee Error
+	return asError(err, &ee) && !ee.expiresAt.IsZero() && time.Now().After(ee.expiresAt)
+}
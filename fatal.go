@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FatalHandler installs logger as the handler of fatal errors, passed to
+// LogFatal or recovered by ExitOnPanic: the error is logged, a crash report
+// is written to dir unless it is empty, the logger is synced so the final
+// entry is not lost, and the process exits with ExitCode. Like Configure it
+// is meant to be called once during program start-up.
+func FatalHandler(logger *zap.Logger, dir string) {
+	Configure(func(c *config) {
+		c.fatalLogger = logger
+		c.crashDir = dir
+	})
+}
+
+// LogFatal handles err with the installed FatalHandler, the global logger
+// when none is, and exits.
+func LogFatal(err error) {
+	if err == nil {
+		err = errorf(zapcore.ErrorLevel, 1, "fatal error")
+	}
+	logger := current().fatalLogger
+	if logger == nil {
+		logger = zap.L()
+	}
+	// Fatal entries would make zap exit before the report is written.
+	level := levelOf(err)
+	if level > zapcore.ErrorLevel {
+		level = zapcore.ErrorLevel
+	}
+	if entry := logger.Check(level, err.Error()); entry != nil {
+		entry.Write(Field(err), zap.Bool("fatal", true))
+	}
+	if current().crashDir != "" {
+		if reportErr := writeCrashReport(err); reportErr != nil {
+			logger.Error("writing crash report", zap.Error(reportErr))
+		}
+	}
+	_ = logger.Sync()
+	os.Exit(ExitCode(err))
+}
+
+// ExitOnPanic handles panics with LogFatal. It must be deferred directly, at
+// the top of main and of goroutines:
+//
+//	defer errors.ExitOnPanic()
+func ExitOnPanic() {
+	if value := recover(); value != nil {
+		LogFatal(FromPanic(value))
+	}
+}
+
+var exitCodes = map[Kind]int{
+	KindInvalid:          64,
+	KindNotFound:         66,
+	KindUnavailable:      69,
+	KindInternal:         70,
+	KindTimeout:          75,
+	KindPermissionDenied: 77,
+	KindUnauthenticated:  77,
+}
+
+// ExitCode returns the exit code of a process failing with err: 2 for
+// panics, like the Go runtime, the sysexits.h code matching its kind, or 1.
+func ExitCode(err error) int {
+	if IsPanic(err) {
+		return 2
+	}
+	var ee Error
+	if asError(err, &ee) {
+		if code, ok := exitCodes[ee.kind]; ok {
+			return code
+		}
+	}
+	return 1
+}
+
+func writeCrashReport(err error) error {
+	name := fmt.Sprintf("crash-%s-%d.json", time.Now().UTC().Format("20060102T150405"), os.Getpid())
+	file, createErr := os.Create(filepath.Join(current().crashDir, name))
+	if createErr != nil {
+		return createErr
+	}
+	_, writeErr := Snapshot(err).WriteTo(file)
+	if closeErr := file.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	return writeErr
+}
@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"go.uber.org/zap/zapcore"
+	"sort"
+	"time"
+)
+
+// WithField attaches a structured field, logged inline in the error object.
+func (ee Error) WithField(key string, value interface{}) Error {
+	fields := make(map[string]interface{}, len(ee.fields)+1)
+	for k, v := range ee.fields {
+		fields[k] = v
+	}
+	fields[key] = value
+	ee.fields = fields
+	return ee.enriched()
+}
+
+// WithFields attaches several structured fields at once, see WithField.
+func (ee Error) WithFields(fields map[string]interface{}) Error {
+	merged := make(map[string]interface{}, len(ee.fields)+len(fields))
+	for k, v := range ee.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	ee.fields = merged
+	return ee.enriched()
+}
+
+// FieldOf returns the value of the field set under key with WithField on the
+// first Error in the chain of err.
+func FieldOf(err error, key string) (interface{}, bool) {
+	var ee Error
+	if !asError(err, &ee) {
+		return nil, false
+	}
+	value, ok := ee.fields[key]
+	return value, ok
+}
+
+func addFields(encoder zapcore.ObjectEncoder, fields map[string]interface{}) error {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := addField(encoder, key, fields[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addField(encoder zapcore.ObjectEncoder, key string, value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		encoder.AddString(key, v)
+	case bool:
+		encoder.AddBool(key, v)
+	case int:
+		encoder.AddInt(key, v)
+	case int64:
+		encoder.AddInt64(key, v)
+	case uint64:
+		encoder.AddUint64(key, v)
+	case float64:
+		encoder.AddFloat64(key, v)
+	case time.Duration:
+		encoder.AddDuration(key, v)
+	case time.Time:
+		encoder.AddTime(key, v)
+	case zapcore.ObjectMarshaler:
+		return encoder.AddObject(key, v)
+	case zapcore.ArrayMarshaler:
+		return encoder.AddArray(key, v)
+	case error:
+		encoder.AddString(key, v.Error())
+	default:
+		return encoder.AddReflected(key, v)
+	}
+	return nil
+}
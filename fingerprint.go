@@ -0,0 +1,26 @@
+package errors
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+)
+
+// Fingerprint identifies errors that are considered identical: same message,
+// code and kind, created at the same place.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	hash := fnv.New64a()
+	var ee Error
+	if asError(err, &ee) {
+		_, _ = fmt.Fprintf(hash, "%s\x00%d\x00%s", ee.base.Message(), ee.base.Code(), ee.kind)
+		if len(ee.stacktrace) > 0 {
+			_, _ = fmt.Fprintf(hash, "\x00%s:%d", ee.stacktrace[0].Function, ee.stacktrace[0].Line)
+		}
+	} else {
+		_, _ = fmt.Fprintf(hash, "%T\x00%s", err, err.Error())
+	}
+	return strconv.FormatUint(hash.Sum64(), 16)
+}
@@ -0,0 +1,53 @@
+package errors
+
+import "context"
+
+// FlagsFunc returns the feature flags, or experiment variants, active for
+// ctx, typically by querying the flag client.
+type FlagsFunc func(ctx context.Context) map[string]bool
+
+// Flags sets the function WrapCtx and WithFlagsFrom read the active feature
+// flags from.
+func Flags(source FlagsFunc) Option {
+	return func(c *config) {
+		c.flags = source
+	}
+}
+
+// WithFlags records the feature flags active when the error occurred, as
+// the flags field, to debug failures happening under specific flag
+// combinations only. Flags are merged with the ones already recorded.
+func (ee Error) WithFlags(flags map[string]bool) Error {
+	if len(flags) == 0 {
+		return ee
+	}
+	existing, _ := ee.fields["flags"].(map[string]bool)
+	merged := make(map[string]bool, len(existing)+len(flags))
+	for name, enabled := range existing {
+		merged[name] = enabled
+	}
+	for name, enabled := range flags {
+		merged[name] = enabled
+	}
+	return ee.WithField("flags", merged)
+}
+
+// WithFlagsFrom records the feature flags the function set with Flags
+// returns for ctx, see WithFlags.
+func (ee Error) WithFlagsFrom(ctx context.Context) Error {
+	source := current().flags
+	if source == nil {
+		return ee
+	}
+	return ee.WithFlags(source(ctx))
+}
+
+// FlagsOf returns the feature flags recorded in the chain of err.
+func FlagsOf(err error) map[string]bool {
+	var flags map[string]bool
+	anyError(err, func(ee Error) bool {
+		flags, _ = ee.fields["flags"].(map[string]bool)
+		return flags != nil
+	})
+	return flags
+}
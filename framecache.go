@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"runtime"
+	"sync"
+)
+
+const (
+	frameCacheShards    = 16
+	frameCacheShardSize = 512
+)
+
+// frameCache memoizes the symbolization of program counters, which is the
+// bulk of the cost of capturing a stacktrace on hot error paths. A single PC
+// may resolve to several frames when calls were inlined. Cached frames are
+// shared between errors and must not be modified.
+var frameCache = newPCFrameCache()
+
+type pcFrameCache struct {
+	shards [frameCacheShards]pcFrameCacheShard
+}
+
+type pcFrameCacheShard struct {
+	mu     sync.RWMutex
+	frames map[uintptr][]*runtime.Frame
+}
+
+func newPCFrameCache() *pcFrameCache {
+	cache := &pcFrameCache{}
+	for i := range cache.shards {
+		cache.shards[i].frames = make(map[uintptr][]*runtime.Frame)
+	}
+	return cache
+}
+
+func (c *pcFrameCache) frames(pc uintptr) []*runtime.Frame {
+	shard := &c.shards[(pc>>4)%frameCacheShards]
+	shard.mu.RLock()
+	frames, ok := shard.frames[pc]
+	shard.mu.RUnlock()
+	if ok {
+		return frames
+	}
+	frames = resolvePC(pc)
+	shard.mu.Lock()
+	if len(shard.frames) >= frameCacheShardSize {
+		shard.frames = make(map[uintptr][]*runtime.Frame)
+	}
+	shard.frames[pc] = frames
+	shard.mu.Unlock()
+	return frames
+}
+
+func resolvePC(pc uintptr) []*runtime.Frame {
+	resolved := current().symbolizer.Symbolize(pc)
+	frames := make([]*runtime.Frame, len(resolved))
+	for i := range resolved {
+		if resolved[i].PC == 0 {
+			resolved[i].PC = pc
+		}
+		frames[i] = &resolved[i]
+	}
+	return frames
+}
+
+// Symbolizer resolves a program counter, as returned by runtime.Callers,
+// into frames, several when calls were inlined, innermost first. Custom
+// symbolizers serve stripped binaries or builds whose symbols live on a
+// symbol server.
+type Symbolizer interface {
+	Symbolize(pc uintptr) []runtime.Frame
+}
+
+// Symbols sets the Symbolizer resolving stacktraces, the Go runtime by
+// default. Resolved frames are cached, so it must be set before errors are
+// created.
+func Symbols(symbolizer Symbolizer) Option {
+	return func(c *config) {
+		c.symbolizer = symbolizer
+	}
+}
+
+// runtimeSymbolizer resolves program counters with the tables of the running
+// binary.
+type runtimeSymbolizer struct{}
+
+func (runtimeSymbolizer) Symbolize(pc uintptr) []runtime.Frame {
+	iterator := runtime.CallersFrames([]uintptr{pc})
+	var frames []runtime.Frame
+	for {
+		frame, more := iterator.Next()
+		frames = append(frames, frame)
+		if !more {
+			return frames
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func inlinedErrorf() Error {
+	return Errorf("inlined")
+}
+
+//go:noinline
+func callsInlined() Error {
+	return inlinedErrorf()
+}
+
+func TestInlinedFramesSymbolized(t *testing.T) {
+	want := []string{"inlinedErrorf", "callsInlined", "TestInlinedFramesSymbolized"}
+	// The second round is served by the frame cache.
+	for round := 0; round < 2; round++ {
+		frames := callsInlined().stacktrace
+		if len(frames) < len(want) {
+			t.Fatalf("round %d: got %d frames, want at least %d", round, len(frames), len(want))
+		}
+		for i, name := range want {
+			if function := frames[i].Function; function != packagePath+"."+name {
+				t.Errorf("round %d: frame %d is %s, want %s", round, i, function, name)
+			}
+			if !strings.HasSuffix(frames[i].File, "framecache_test.go") || frames[i].Line == 0 {
+				t.Errorf("round %d: frame %d at %s:%d", round, i, frames[i].File, frames[i].Line)
+			}
+		}
+	}
+}
+
+func BenchmarkStackTraceCached(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = appendStackTrace(nil, 0)
+	}
+}
+
+// BenchmarkStackTraceUncached resolves the same stacktrace without the frame
+// cache, as done before it was introduced.
+func BenchmarkStackTraceUncached(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var pc [10]uintptr
+		n := runtime.Callers(2, pc[:])
+		var frames []*runtime.Frame
+		for _, pc := range pc[:n] {
+			frames = append(frames, resolvePC(pc)...)
+		}
+		_ = frames
+	}
+}
@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+func init() {
+	causeEnrichers = append(causeEnrichers, enrichFS)
+}
+
+// enrichFS records op, path and errno of *fs.PathError and *os.LinkError as
+// fs.* fields and derives the kind from the errno.
+func enrichFS(ee Error, cause error) Error {
+	fields := map[string]interface{}{}
+	var pathError *fs.PathError
+	var linkError *os.LinkError
+	switch {
+	case errors.As(cause, &pathError):
+		fields["fs.op"] = pathError.Op
+		fields["fs.path"] = pathError.Path
+	case errors.As(cause, &linkError):
+		fields["fs.op"] = linkError.Op
+		fields["fs.old"] = linkError.Old
+		fields["fs.new"] = linkError.New
+	default:
+		return ee
+	}
+	var errno syscall.Errno
+	if errors.As(cause, &errno) {
+		fields["fs.errno"] = int(errno)
+	}
+	ee = ee.WithFields(fields)
+	switch {
+	case errors.Is(cause, fs.ErrNotExist):
+		ee = ee.setKind(KindNotFound)
+	case errors.Is(cause, fs.ErrExist):
+		ee = ee.setKind(KindConflict)
+	case errors.Is(cause, fs.ErrPermission):
+		ee = ee.setKind(KindPermissionDenied)
+	case errors.Is(cause, os.ErrDeadlineExceeded):
+		ee = ee.setKind(KindTimeout)
+	}
+	return ee
+}
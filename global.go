@@ -0,0 +1,128 @@
+package errors
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogGlobal logs err with the global logger, see zap.L.
+func LogGlobal(err error) {
+	Log(zap.L(), err)
+}
+
+// ReplaceGlobalsHook makes the global loggers expand Errors passed as plain
+// error fields, such as zap.Error(err) or err given to zap.S().Errorw, into
+// the structured error object. It returns a function restoring the previous
+// globals.
+func ReplaceGlobalsHook() func() {
+	return zap.ReplaceGlobals(zap.L().WithOptions(ExpandErrors()))
+}
+
+// ExpandErrors is a zap option expanding Errors passed as plain error fields
+// into the structured error object.
+func ExpandErrors() zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return errorCore{Core: core}
+	})
+}
+
+type errorCore struct {
+	zapcore.Core
+}
+
+func (c errorCore) With(fields []zapcore.Field) zapcore.Core {
+	return errorCore{Core: c.Core.With(expandFields(fields))}
+}
+
+func (c errorCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c errorCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return writeChecked(c.Core, entry, expandFields(fields))
+}
+
+// writeChecked writes entry to core if core accepts it. Cores rewriting
+// fields add themselves in Check and write through writeChecked, so the
+// sampling and levels of the cores they wrap, such as the cores of a Tee,
+// still apply.
+func writeChecked(core zapcore.Core, entry zapcore.Entry, fields []zapcore.Field) error {
+	if checked := core.Check(entry, nil); checked != nil {
+		checked.Write(fields...)
+	}
+	return nil
+}
+
+func expandFields(fields []zapcore.Field) []zapcore.Field {
+	var expanded []zapcore.Field
+	for i, field := range fields {
+		if field.Type != zapcore.ErrorType {
+			continue
+		}
+		err, ok := field.Interface.(error)
+		var ee Error
+		if !ok || !asError(err, &ee) {
+			continue
+		}
+		if expanded == nil {
+			expanded = append([]zapcore.Field(nil), fields...)
+		}
+		expanded[i] = zap.Object(field.Key, ee)
+	}
+	if expanded == nil {
+		return fields
+	}
+	return expanded
+}
+
+// SuppressStacktraces is a zap option dropping the stacktrace zap adds to
+// entries, see zap.AddStacktrace, when they carry an Error with its own
+// stacktrace, so entries hold a single stack: where the error was created
+// rather than where it was logged.
+func SuppressStacktraces() zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return stackCore{Core: core}
+	})
+}
+
+type stackCore struct {
+	zapcore.Core
+	hasStack bool
+}
+
+func (c stackCore) With(fields []zapcore.Field) zapcore.Core {
+	return stackCore{Core: c.Core.With(fields), hasStack: c.hasStack || hasErrorStack(fields)}
+}
+
+func (c stackCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c stackCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if c.hasStack || hasErrorStack(fields) {
+		entry.Stack = ""
+	}
+	return writeChecked(c.Core, entry, fields)
+}
+
+// hasErrorStack reports whether fields hold an Error with a stacktrace,
+// passed as an error or as the object built by Field.
+func hasErrorStack(fields []zapcore.Field) bool {
+	for _, field := range fields {
+		ee, ok := fieldError(field)
+		if !ok && (field.Type == zapcore.ErrorType || field.Type == zapcore.ObjectMarshalerType) {
+			err, isErr := field.Interface.(error)
+			ok = isErr && asError(err, &ee)
+		}
+		if ok && len(ee.stacktrace) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+	"time"
+)
+
+// wrappingOptions are the zap options wrapping cores, which must keep the
+// sampling and levels of the cores they wrap.
+var wrappingOptions = map[string]func(*zap.Logger) *zap.Logger{
+	"ExpandErrors": func(logger *zap.Logger) *zap.Logger {
+		return logger.WithOptions(ExpandErrors())
+	},
+	"SuppressStacktraces": func(logger *zap.Logger) *zap.Logger {
+		return logger.WithOptions(SuppressStacktraces())
+	},
+	"Redact": func(logger *zap.Logger) *zap.Logger {
+		return Redact(logger, SummaryRedaction)
+	},
+	"RenderWith": func(logger *zap.Logger) *zap.Logger {
+		return logger.WithOptions(RenderWith(CurrentConfig()))
+	},
+}
+
+func TestWrappingCoresKeepSampling(t *testing.T) {
+	for name, wrap := range wrappingOptions {
+		core, logs := observer.New(zap.DebugLevel)
+		sampled := zapcore.NewSamplerWithOptions(core, time.Minute, 10, 100)
+		logger := wrap(zap.New(sampled))
+		err := Errorf("failure")
+		for i := 0; i < 1000; i++ {
+			logger.Error("failure", zap.Error(err))
+		}
+		if n := logs.Len(); n != 19 {
+			t.Errorf("%s: %d of 1000 entries logged, the sampler lets 19 through", name, n)
+		}
+	}
+}
+
+func TestWrappingCoresKeepTeeLevels(t *testing.T) {
+	for name, wrap := range wrappingOptions {
+		infoCore, infoLogs := observer.New(zap.InfoLevel)
+		errorCore, errorLogs := observer.New(zap.ErrorLevel)
+		logger := wrap(zap.New(zapcore.NewTee(infoCore, errorCore)))
+
+		logger.Info("progress", zap.Error(Errorf("retrying")))
+
+		if infoLogs.Len() != 1 {
+			t.Errorf("%s: got %d info entries, want 1", name, infoLogs.Len())
+		}
+		if errorLogs.Len() != 0 {
+			t.Errorf("%s: an info entry reached the error core", name)
+		}
+	}
+}
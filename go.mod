@@ -2,7 +2,11 @@ module github.com/jpascal/zap-errors
 
 go 1.18
 
-require go.uber.org/zap v1.21.0
+require (
+	github.com/BurntSushi/toml v1.3.2
+	go.uber.org/zap v1.21.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	go.uber.org/atomic v1.7.0 // indirect
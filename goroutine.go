@@ -0,0 +1,34 @@
+package errors
+
+import (
+	"context"
+	"go.uber.org/zap"
+)
+
+// Go runs fn in a new goroutine, logging a panic as an Error instead of
+// letting it crash the process.
+func Go(logger *zap.Logger, fn func()) {
+	go func() {
+		defer func() {
+			if value := recover(); value != nil {
+				Log(logger, FromPanic(value))
+			}
+		}()
+		fn()
+	}()
+}
+
+// GoCtx runs fn in a new goroutine, logging the error it returns or the
+// panic it raises.
+func GoCtx(ctx context.Context, logger *zap.Logger, fn func(ctx context.Context) error) {
+	go func() {
+		defer func() {
+			if value := recover(); value != nil {
+				Log(logger, FromPanic(value))
+			}
+		}()
+		if err := fn(ctx); err != nil {
+			Log(logger, EnsureStack(err))
+		}
+	}()
+}
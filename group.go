@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+// Group mirrors golang.org/x/sync/errgroup.Group, but Wait reports the
+// failures of all goroutines rather than only the first one. A zero Group is
+// valid.
+type Group struct {
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	errs   []error
+	cancel context.CancelFunc
+}
+
+// GroupWithContext returns a Group and a context derived from ctx that is
+// canceled when a goroutine of the group first fails or when Wait returns.
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go runs f in a new goroutine. Failures are given a stacktrace captured in
+// that goroutine if they do not carry one already.
+func (g *Group) Go(f func() error) {
+	g.mu.Lock()
+	index := len(g.errs)
+	g.errs = append(g.errs, nil)
+	g.mu.Unlock()
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := f(); err != nil {
+			err = EnsureStack(err)
+			g.mu.Lock()
+			g.errs[index] = err
+			g.mu.Unlock()
+			if g.cancel != nil {
+				g.cancel()
+			}
+		}
+	}()
+}
+
+// Wait blocks until all goroutines returned and reports their failures as
+// one Error built with WrapAll, indexed in launch order, or nil.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return WrapAll(g.errs, "%d goroutines failed", countFailures(g.errs))
+}
+
+func countFailures(errs []error) int {
+	failures := 0
+	for _, err := range errs {
+		if err != nil {
+			failures++
+		}
+	}
+	return failures
+}
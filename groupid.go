@@ -0,0 +1,21 @@
+package errors
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// NewGroupID returns a random identifier to correlate related errors, such
+// as all the failures of one batch job run, with WithGroupID.
+func NewGroupID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// WithGroupID sets the group identifier logged with the error, see
+// NewGroupID.
+func (ee Error) WithGroupID(id string) Error {
+	ee.groupID = id
+	return ee.enriched()
+}
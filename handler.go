@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"go.uber.org/zap"
+	"sort"
+)
+
+// Handler consumes errors. Its method set is the one of emperror handlers,
+// so they can be used as Handlers, and Handlers passed where emperror
+// expects one.
+type Handler interface {
+	Handle(err error)
+}
+
+// HandlerFunc adapts a function to Handler.
+type HandlerFunc func(err error)
+
+func (f HandlerFunc) Handle(err error) {
+	f(err)
+}
+
+// HandlerFromLogger returns a Handler logging errors with Log.
+func HandlerFromLogger(logger *zap.Logger) Handler {
+	return HandlerFunc(func(err error) {
+		Log(logger, err)
+	})
+}
+
+// Details returns the code, kind and fields of the error as alternating keys
+// and values, the form emperror handlers read error context from through
+// errors.GetDetails.
+func (ee Error) Details() []interface{} {
+	var details []interface{}
+	conf := current()
+	keyNames := conf.keys
+	if code := resolveCode(ee, conf); code != 0 {
+		details = append(details, keyNames.Code, code)
+	}
+	if ee.kind != KindUnknown {
+		details = append(details, keyNames.Kind, string(ee.kind))
+	}
+	keys := make([]string, 0, len(ee.fields))
+	for key := range ee.fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		details = append(details, key, ee.fields[key])
+	}
+	return details
+}
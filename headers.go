@@ -0,0 +1,62 @@
+package errors
+
+import "strconv"
+
+// Header keys written by EncodeHeaders.
+const (
+	HeaderCode        = "error-code"
+	HeaderKind        = "error-kind"
+	HeaderMessage     = "error-message"
+	HeaderFingerprint = "error-fingerprint"
+)
+
+// RecordHeader is a message header, shaped like the record headers of the
+// common Kafka clients (sarama, franz-go, kafka-go).
+type RecordHeader struct {
+	Key   string
+	Value []byte
+}
+
+// EncodeHeaders describes err in message headers, so dead-letter consumers
+// can triage failures without parsing message bodies.
+func EncodeHeaders(err error) []RecordHeader {
+	if err == nil {
+		return nil
+	}
+	headers := []RecordHeader{
+		{Key: HeaderMessage, Value: []byte(err.Error())},
+		{Key: HeaderFingerprint, Value: []byte(Fingerprint(err))},
+	}
+	var ee Error
+	if asError(err, &ee) {
+		if code := resolveCode(err, current()); code != 0 {
+			headers = append(headers, RecordHeader{Key: HeaderCode, Value: []byte(strconv.Itoa(code))})
+		}
+		if ee.kind != KindUnknown {
+			headers = append(headers, RecordHeader{Key: HeaderKind, Value: []byte(ee.kind)})
+		}
+	}
+	return headers
+}
+
+// DecodeHeaders restores the error described by EncodeHeaders. The
+// fingerprint is kept as the fingerprint field. It reports false when the
+// headers do not describe an error.
+func DecodeHeaders(headers []RecordHeader) (Error, bool) {
+	ee := Error{mark: newMark()}
+	found := false
+	for _, header := range headers {
+		switch header.Key {
+		case HeaderMessage:
+			ee.base, found = ee.base.WithText(string(header.Value)), true
+		case HeaderCode:
+			code, _ := strconv.Atoi(string(header.Value))
+			ee.base = ee.base.WithCode(code)
+		case HeaderKind:
+			ee.kind = Kind(header.Value)
+		case HeaderFingerprint:
+			ee.fields = map[string]interface{}{"fingerprint": string(header.Value)}
+		}
+	}
+	return ee, found
+}
@@ -0,0 +1,79 @@
+package httperrors
+
+import (
+	errors "github.com/jpascal/zap-errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+	"time"
+)
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	if sr.status == 0 {
+		sr.status = status
+	}
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Write(data []byte) (int, error) {
+	if sr.status == 0 {
+		sr.status = http.StatusOK
+	}
+	n, err := sr.ResponseWriter.Write(data)
+	sr.bytes += n
+	return n, err
+}
+
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// AccessLog is a middleware writing one access log entry per request, with
+// the error recorded by the handler, if any, as a structured error object.
+// Entries with an error are written at the error's level, 5xx responses at
+// error level and the others at info level. Errors included in the access log
+// are marked as logged.
+func AccessLog(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			// A nil error prepares the context for errors.RecordError, unless
+			// an enclosing middleware did and an error is recorded already.
+			if errors.FromContext(r.Context()) == nil {
+				r = r.WithContext(errors.IntoContext(r.Context(), nil))
+			}
+			recorder := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+			if recorder.status == 0 {
+				recorder.status = http.StatusOK
+			}
+			err := errors.FromContext(r.Context())
+			level := zapcore.InfoLevel
+			switch {
+			case err != nil:
+				level = errors.LevelOf(err)
+			case recorder.status >= 500:
+				level = zapcore.ErrorLevel
+			}
+			if entry := logger.Check(level, "request"); entry != nil {
+				entry.Write(
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.Int("status", recorder.status),
+					zap.Int("bytes", recorder.bytes),
+					zap.Duration("latency", time.Since(start)),
+					errors.Field(err),
+				)
+			}
+			if err != nil {
+				errors.SetLogged(err)
+			}
+		})
+	}
+}
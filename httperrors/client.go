@@ -0,0 +1,104 @@
+// Package httperrors connects the errors of github.com/jpascal/zap-errors to
+// net/http: errors built from failed responses and transport failures for
+// API clients, error responses and access logs for servers.
+package httperrors
+
+import (
+	errors "github.com/jpascal/zap-errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// BodyLimit bounds the number of response body bytes FromResponse keeps.
+const BodyLimit = 4096
+
+// Response is the payload of errors built by FromResponse.
+type Response struct {
+	Method string      `json:"method,omitempty"`
+	URL    string      `json:"url,omitempty"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// FromResponse builds an Error from a failed response for API clients. The
+// status becomes the code and decides the kind, the request method and URL,
+// the response headers and up to BodyLimit bytes of the body become the
+// payload. A Retry-After header marks the error retryable and is recorded as
+// the retry_after field. The body is read but not closed.
+func FromResponse(resp *http.Response) errors.Error {
+	payload := Response{Status: resp.StatusCode, Header: resp.Header.Clone()}
+	payload.Header.Del("Set-Cookie")
+	message := resp.Status
+	if resp.Request != nil {
+		payload.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			payload.URL = resp.Request.URL.Redacted()
+		}
+		message = payload.Method + " " + payload.URL + ": " + resp.Status
+	}
+	if resp.Body != nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, BodyLimit))
+		payload.Body = string(body)
+	}
+	ee := errors.ErrorfSkip(1, "%s", message).
+		WithCode(resp.StatusCode).
+		WithPayload(payload).
+		WithKind(kindOfStatus(resp.StatusCode))
+	if after, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		ee = ee.WithField("retry_after", after).WithRetryable(true)
+	}
+	return ee
+}
+
+// RetryAfter returns the delay requested by the server through the
+// Retry-After header of the response err was built from.
+func RetryAfter(err error) (time.Duration, bool) {
+	value, _ := errors.FieldOf(err, "retry_after")
+	after, ok := value.(time.Duration)
+	return after, ok
+}
+
+func kindOfStatus(status int) errors.Kind {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return errors.KindInvalid
+	case http.StatusUnauthorized:
+		return errors.KindUnauthenticated
+	case http.StatusForbidden:
+		return errors.KindPermissionDenied
+	case http.StatusNotFound, http.StatusGone:
+		return errors.KindNotFound
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return errors.KindConflict
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return errors.KindTimeout
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return errors.KindUnavailable
+	}
+	switch {
+	case status >= 500:
+		return errors.KindInternal
+	case status >= 400:
+		return errors.KindInvalid
+	}
+	return errors.KindUnknown
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if after := time.Until(at); after > 0 {
+			return after, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,89 @@
+package httperrors
+
+import (
+	"encoding/json"
+	errors "github.com/jpascal/zap-errors"
+	"net/http"
+	"strconv"
+)
+
+// BodyFormat selects the body written by ResponseWriter.WriteError.
+type BodyFormat int
+
+const (
+	// ProblemJSON writes RFC 7807 application/problem+json bodies.
+	ProblemJSON BodyFormat = iota
+	// JSONAPI writes JSON:API error documents.
+	JSONAPI
+)
+
+// ResponseWriter decorates an http.ResponseWriter with WriteError.
+type ResponseWriter struct {
+	http.ResponseWriter
+	request *http.Request
+	format  BodyFormat
+	err     error
+}
+
+func NewResponseWriter(w http.ResponseWriter, r *http.Request, format BodyFormat) *ResponseWriter {
+	return &ResponseWriter{ResponseWriter: w, request: r, format: format}
+}
+
+// WriteError responds with the status errors.StatusOf maps err to and a body
+// in the configured format, and records err for the middlewares wrapping the
+// handler, see errors.RecordError. Messages of server errors are not exposed
+// in the body.
+func (rw *ResponseWriter) WriteError(err error) {
+	if err == nil {
+		return
+	}
+	rw.err = err
+	errors.RecordError(rw.request, err)
+	status := errors.StatusOf(err)
+	detail := ""
+	if status < 500 {
+		detail = err.Error()
+	}
+	code := errors.CodeOf(err)
+	var body interface{}
+	switch rw.format {
+	case JSONAPI:
+		rw.Header().Set("Content-Type", "application/vnd.api+json")
+		item := map[string]interface{}{
+			"status": strconv.Itoa(status),
+			"title":  http.StatusText(status),
+		}
+		if code != 0 {
+			item["code"] = strconv.Itoa(code)
+		}
+		if detail != "" {
+			item["detail"] = detail
+		}
+		body = map[string]interface{}{"errors": []interface{}{item}}
+	default:
+		rw.Header().Set("Content-Type", "application/problem+json")
+		problem := map[string]interface{}{
+			"type":   "about:blank",
+			"title":  http.StatusText(status),
+			"status": status,
+		}
+		if code != 0 {
+			problem["code"] = code
+		}
+		if detail != "" {
+			problem["detail"] = detail
+		}
+		body = problem
+	}
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(body)
+}
+
+// Err returns the error written with WriteError.
+func (rw *ResponseWriter) Err() error {
+	return rw.err
+}
+
+func (rw *ResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,77 @@
+package httperrors
+
+import (
+	"context"
+	stderrors "errors"
+	errors "github.com/jpascal/zap-errors"
+	"go.uber.org/zap"
+	"net"
+	"net/http"
+	"time"
+)
+
+type attemptKey struct{}
+
+// ContextWithAttempt records in ctx which attempt of a retried request is
+// being made, for Transport to report it.
+func ContextWithAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// Transport is an http.RoundTripper turning transport failures, and
+// optionally non-2xx responses, into Errors. Errors carry the request method
+// and URL as payload and the attempt and latency as fields, and are logged
+// when Logger is set.
+type Transport struct {
+	// Base performs the requests, http.DefaultTransport when nil.
+	Base http.RoundTripper
+	// Logger, when set, logs every error returned.
+	Logger *zap.Logger
+	// StatusErrors makes non-2xx responses fail with FromResponse. The
+	// response body is closed in that case.
+	StatusErrors bool
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	attempt, ok := req.Context().Value(attemptKey{}).(int)
+	if !ok {
+		attempt = 1
+	}
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	latency := time.Since(start)
+	var ee errors.Error
+	switch {
+	case err != nil:
+		ee = errors.WithMessage(err, "%s %s", req.Method, req.URL.Redacted()).
+			WithPayload(Response{Method: req.Method, URL: req.URL.Redacted()})
+		// The cause enrichers run by WithMessage know TLS, network and
+		// context failures better than the generic fallbacks.
+		if errors.KindOf(ee) == errors.KindUnknown {
+			var netErr net.Error
+			switch {
+			case stderrors.Is(err, context.Canceled):
+				ee = ee.WithKind(errors.KindCanceled)
+			case stderrors.As(err, &netErr) && netErr.Timeout():
+				ee = ee.WithKind(errors.KindTimeout)
+			default:
+				ee = ee.WithKind(errors.KindUnavailable)
+			}
+		}
+	case t.StatusErrors && (resp.StatusCode < 200 || resp.StatusCode > 299):
+		ee = FromResponse(resp)
+		_ = resp.Body.Close()
+		resp = nil
+	default:
+		return resp, nil
+	}
+	ee = ee.WithFields(map[string]interface{}{"attempt": attempt, "latency": latency})
+	if t.Logger != nil {
+		errors.Log(t.Logger, ee)
+	}
+	return resp, ee
+}
@@ -0,0 +1,46 @@
+package httperrors
+
+import (
+	"context"
+	"crypto/x509"
+	errors "github.com/jpascal/zap-errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type failingTransport struct {
+	err error
+}
+
+func (t failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+func TestTransportKeepsEnrichedKinds(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		err       error
+		kind      errors.Kind
+		retryable bool
+	}{
+		{"tls", x509.UnknownAuthorityError{}, errors.KindTLS, false},
+		{"canceled", context.Canceled, errors.KindCanceled, false},
+		{"deadline", context.DeadlineExceeded, errors.KindTimeout, true},
+		{"other", http.ErrHandlerTimeout, errors.KindUnavailable, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &Transport{Base: failingTransport{err: tt.err}}
+			_, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com/", nil))
+			if _, ok := err.(errors.Error); !ok {
+				t.Fatalf("got %T, want an Error", err)
+			}
+			if kind := errors.KindOf(err); kind != tt.kind {
+				t.Errorf("got kind %q, want %q", kind, tt.kind)
+			}
+			if errors.IsRetryable(err) != tt.retryable {
+				t.Errorf("got retryable %v, want %v", errors.IsRetryable(err), tt.retryable)
+			}
+		})
+	}
+}
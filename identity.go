@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// IdentityMode selects how tenant and user identifiers are logged.
+type IdentityMode int
+
+const (
+	// IdentityPlain logs identifiers as they are.
+	IdentityPlain IdentityMode = iota
+	// IdentityHashed logs a salted SHA-256 digest of identifiers, which
+	// still correlates the entries of a tenant or user.
+	IdentityHashed
+	// IdentityMasked logs the first and last characters of identifiers
+	// only, a quarter of them and at most four on each side. Identifiers
+	// shorter than four characters are masked entirely.
+	IdentityMasked
+)
+
+// IdentityPolicy configures the rendering of the identifiers set with
+// WithTenant and WithUser, for compliance with data protection rules. The
+// exact values stay available in the process through TenantOf and UserOf.
+type IdentityPolicy struct {
+	Mode IdentityMode
+	// Salt is prepended to identifiers before hashing.
+	Salt string
+}
+
+// Identities sets the policy applied to logged tenant and user identifiers.
+func Identities(policy IdentityPolicy) Option {
+	return func(c *config) {
+		c.identities = policy
+	}
+}
+
+func (p IdentityPolicy) render(id string) string {
+	switch p.Mode {
+	case IdentityHashed:
+		sum := sha256.Sum256([]byte(p.Salt + id))
+		return hex.EncodeToString(sum[:16])
+	case IdentityMasked:
+		runes := []rune(id)
+		shown := len(runes) / 4
+		if shown > 4 {
+			shown = 4
+		}
+		return string(runes[:shown]) + strings.Repeat("*", len(runes)-2*shown) + string(runes[len(runes)-shown:])
+	}
+	return id
+}
+
+// WithTenant attributes the error to a tenant, logged as tenant according to
+// the IdentityPolicy.
+func (ee Error) WithTenant(id string) Error {
+	ee.tenant = id
+	return ee.enriched()
+}
+
+// WithUser attributes the error to a user, logged as user according to the
+// IdentityPolicy.
+func (ee Error) WithUser(id string) Error {
+	ee.user = id
+	return ee.enriched()
+}
+
+// TenantOf returns the exact tenant identifier set with WithTenant in the
+// chain of err.
+func TenantOf(err error) (string, bool) {
+	var tenant string
+	found := anyError(err, func(ee Error) bool {
+		tenant = ee.tenant
+		return tenant != ""
+	})
+	return tenant, found
+}
+
+// UserOf returns the exact user identifier set with WithUser in the chain of
+// err.
+func UserOf(err error) (string, bool) {
+	var user string
+	found := anyError(err, func(ee Error) bool {
+		user = ee.user
+		return user != ""
+	})
+	return user, found
+}
@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+)
+
+func TestIdentityMasked(t *testing.T) {
+	masked := IdentityPolicy{Mode: IdentityMasked}
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"a", "*"},
+		{"ab", "**"},
+		{"abc", "***"},
+		{"abcd", "a**d"},
+		{"abcdefgh", "ab****gh"},
+		{"abcdefghijklmnop", "abcd********mnop"},
+		// The quarter is capped at four characters on each side.
+		{"abcdefghijklmnopqrstuvwxyz", "abcd******************wxyz"},
+		// Characters are runes, not bytes.
+		{"éüçñ", "é**ñ"},
+		{"日本語のテナント", "日本****ント"},
+	}
+	for _, tt := range tests {
+		if got := masked.render(tt.id); got != tt.want {
+			t.Errorf("render(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestIdentityHashed(t *testing.T) {
+	digest := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:16])
+	}
+	unsalted := IdentityPolicy{Mode: IdentityHashed}
+	salted := IdentityPolicy{Mode: IdentityHashed, Salt: "pepper"}
+
+	if got := unsalted.render("tenant-1"); got != digest("tenant-1") {
+		t.Errorf("got %q, want the truncated digest of the id", got)
+	}
+	if got := salted.render("tenant-1"); got != digest("peppertenant-1") {
+		t.Errorf("got %q, want the truncated digest of the salted id", got)
+	}
+	if salted.render("tenant-1") == unsalted.render("tenant-1") {
+		t.Error("the salt does not change the digest")
+	}
+	if salted.render("tenant-1") != salted.render("tenant-1") {
+		t.Error("the digest of an id is not stable")
+	}
+	if salted.render("tenant-1") == salted.render("tenant-2") {
+		t.Error("distinct ids share a digest")
+	}
+}
+
+func TestIdentitiesLogged(t *testing.T) {
+	defer CurrentConfig().Apply()
+	Configure(Identities(IdentityPolicy{Mode: IdentityMasked}))
+	core, logs := observer.New(zap.DebugLevel)
+
+	err := Errorf("failure").WithTenant("acme-corp").WithUser("user@example.com")
+	Log(zap.New(core), err)
+
+	object := logs.AllUntimed()[0].ContextMap()["error"].(map[string]interface{})
+	if object["tenant"] != "ac*****rp" || object["user"] != "user********.com" {
+		t.Errorf("got tenant %v and user %v, want them masked", object["tenant"], object["user"])
+	}
+	if tenant, _ := TenantOf(err); tenant != "acme-corp" {
+		t.Errorf("got tenant %q, want the exact id", tenant)
+	}
+}
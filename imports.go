@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"github.com/jpascal/zap-errors/core"
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrorxTrait maps an errorx trait to a kind, Has reporting whether an error
+// has the trait:
+//
+//	errors.ErrorxTrait{
+//		Kind: errors.KindTimeout,
+//		Has:  func(err error) bool { return errorx.HasTrait(err, errorx.Timeout()) },
+//	}
+//
+// Traits without kind, such as errorx.Temporary(), mark errors retryable
+// instead.
+type ErrorxTrait struct {
+	Kind Kind
+	Has  func(err error) bool
+}
+
+// FromErrorx converts an errorx error. The first matching trait with a kind
+// gives the kind of the error.
+func FromErrorx(err error, traits ...ErrorxTrait) Error {
+	ee := imported(err, nil)
+	for _, trait := range traits {
+		if !trait.Has(err) {
+			continue
+		}
+		if trait.Kind == KindUnknown {
+			ee.retry = retryYes
+			continue
+		}
+		if ee.kind == KindUnknown {
+			ee.kind = trait.Kind
+		}
+	}
+	return ee
+}
+
+// FromEris converts an eris error, keeping the stacktrace recorded by eris
+// where it was created instead of capturing one:
+//
+//	errors.FromEris(err, eris.StackFrames(err))
+func FromEris(err error, pcs []uintptr) Error {
+	return imported(err, pcs)
+}
+
+// imported wraps an error of another package. Its stacktrace is resolved
+// from pcs when provided, captured at the caller of the exported function
+// otherwise.
+func imported(err error, pcs []uintptr) Error {
+	statsCreated()
+	ee := Error{base: core.New("", err), mark: newMark()}
+	if err != nil {
+		ee.base = ee.base.WithText(err.Error())
+	}
+	if len(pcs) > 0 {
+		ee.stacktrace = appendFrames(nil, pcs)
+	} else {
+		ee.stacktrace = stackTraceAt(zapcore.ErrorLevel, 1)
+	}
+	return enrichCause(ee, err)
+}
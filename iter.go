@@ -0,0 +1,31 @@
+//go:build go1.23
+
+package errors
+
+import (
+	"iter"
+	"runtime"
+)
+
+// FramesOf iterates over the stacktrace of the first Error in the chain of
+// err, innermost call first.
+func FramesOf(err error) iter.Seq[runtime.Frame] {
+	return func(yield func(runtime.Frame) bool) {
+		var ee Error
+		if !asError(err, &ee) {
+			return
+		}
+		for _, frame := range ee.stacktrace {
+			if !yield(*frame) {
+				return
+			}
+		}
+	}
+}
+
+// CausesOf iterates over err and its causes in the order of Walk.
+func CausesOf(err error) iter.Seq[error] {
+	return func(yield func(error) bool) {
+		Walk(err, yield)
+	}
+}
@@ -0,0 +1,39 @@
+package errors
+
+// Kind classifies an error independently of its message and code.
+type Kind string
+
+const (
+	KindUnknown          Kind = ""
+	KindInvalid          Kind = "invalid"
+	KindNotFound         Kind = "not_found"
+	KindConflict         Kind = "conflict"
+	KindUnauthenticated  Kind = "unauthenticated"
+	KindPermissionDenied Kind = "permission_denied"
+	KindCanceled         Kind = "canceled"
+	KindTimeout          Kind = "timeout"
+	KindUnavailable      Kind = "unavailable"
+	KindInternal         Kind = "internal"
+	KindTLS              Kind = "tls"
+)
+
+func (ee Error) WithKind(kind Kind) Error {
+	ee.kind = kind
+	return ee.enriched()
+}
+
+// KindOf returns the kind of the first Error in the chain of err, or
+// KindUnknown.
+func KindOf(err error) Kind {
+	var ee Error
+	asError(err, &ee)
+	return ee.kind
+}
+
+// HasKind reports whether an Error anywhere in the chain of err, joined
+// children included, has kind.
+func HasKind(err error, kind Kind) bool {
+	return anyError(err, func(ee Error) bool {
+		return ee.kind == kind
+	})
+}
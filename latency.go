@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"encoding/json"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// LatencyHistogram counts logged errors by the time elapsed between their
+// creation and Log. Long latencies reveal errors held in retry loops or
+// queues before surfacing. It implements expvar.Var:
+//
+//	latency := errors.NewLatencyHistogram()
+//	expvar.Publish("error_log_latency", latency)
+//	errors.Configure(errors.LogLatency(latency))
+type LatencyHistogram struct {
+	bounds []time.Duration
+	// counts has one bucket per bound plus one for longer latencies.
+	counts []uint64
+	sum    int64
+}
+
+var defaultLatencyBounds = []time.Duration{
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+	time.Minute,
+}
+
+// NewLatencyHistogram creates a histogram with buckets ending at bounds, from
+// one millisecond to one minute by default.
+func NewLatencyHistogram(bounds ...time.Duration) *LatencyHistogram {
+	if len(bounds) == 0 {
+		bounds = defaultLatencyBounds
+	}
+	bounds = append([]time.Duration(nil), bounds...)
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+	return &LatencyHistogram{bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+}
+
+// LogLatency makes Log record the latency of the errors it writes in
+// histogram. Only errors created after the option is set are measured.
+func LogLatency(histogram *LatencyHistogram) Option {
+	return func(c *config) {
+		c.latency = histogram
+	}
+}
+
+// Observe records latency in the first bucket whose bound is not below it,
+// latencies above every bound in the overflow bucket. It is safe for
+// concurrent use.
+func (h *LatencyHistogram) Observe(latency time.Duration) {
+	i := sort.Search(len(h.bounds), func(i int) bool { return latency <= h.bounds[i] })
+	atomic.AddUint64(&h.counts[i], 1)
+	atomic.AddInt64(&h.sum, int64(latency))
+}
+
+// String renders the cumulative bucket counts, keyed by their upper bound,
+// and the sum of latencies as JSON.
+func (h *LatencyHistogram) String() string {
+	buckets := make(map[string]uint64, len(h.counts))
+	total := uint64(0)
+	for i, bound := range h.bounds {
+		total += atomic.LoadUint64(&h.counts[i])
+		buckets[bound.String()] = total
+	}
+	total += atomic.LoadUint64(&h.counts[len(h.bounds)])
+	buckets["+Inf"] = total
+	data, _ := json.Marshal(map[string]interface{}{
+		"buckets": buckets,
+		"count":   total,
+		"sum":     time.Duration(atomic.LoadInt64(&h.sum)).Seconds(),
+	})
+	return string(data)
+}
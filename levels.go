@@ -0,0 +1,34 @@
+package errors
+
+import "go.uber.org/zap/zapcore"
+
+// LevelPolicy maps codes and kinds to the level errors are logged at when
+// they carry no explicit severity. Codes take precedence over kinds.
+//
+//	errors.Configure(errors.Levels(errors.LevelPolicy{
+//		Kinds: map[errors.Kind]zapcore.Level{
+//			errors.KindNotFound: zapcore.InfoLevel,
+//			errors.KindInternal: zapcore.ErrorLevel,
+//		},
+//	}))
+type LevelPolicy struct {
+	Codes map[int]zapcore.Level
+	Kinds map[Kind]zapcore.Level
+}
+
+// Levels sets the policy deciding log levels from codes and kinds.
+func Levels(policy LevelPolicy) Option {
+	return func(c *config) {
+		c.levels = policy
+	}
+}
+
+func (p LevelPolicy) level(code int, kind Kind) (zapcore.Level, bool) {
+	if level, ok := p.Codes[code]; ok && code != 0 {
+		return level, true
+	}
+	if level, ok := p.Kinds[kind]; ok && kind != KindUnknown {
+		return level, true
+	}
+	return zapcore.ErrorLevel, false
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"sync/atomic"
+	"time"
+)
+
+// RelogPolicy decides what Log does with errors that were logged before.
+type RelogPolicy int
+
+const (
+	// RelogAlways logs errors again.
+	RelogAlways RelogPolicy = iota
+	// RelogSkip drops repeated log calls.
+	RelogSkip
+	// RelogDebug logs repeated calls at debug level.
+	RelogDebug
+)
+
+// Relog sets the policy applied by Log to errors logged before, typically at
+// a lower layer. Values derived from an Error through With* methods and
+// wrapping count as logged when derived from an Error already logged, so
+// wrapping a sentinel does not inherit the logs of earlier wraps. An Error
+// returned as is, such as a package-level sentinel, is a single value: log
+// wrapped copies of it.
+func Relog(policy RelogPolicy) Option {
+	return func(c *config) {
+		c.relog = policy
+	}
+}
+
+// logMark remembers that an Error was logged, and when it was created if log
+// latencies are measured. Plain copies of an Error share its mark, derived
+// Errors get their own.
+type logMark struct {
+	logged  uint32
+	created time.Time
+}
+
+// derive returns the mark of an Error derived from the one holding m: it
+// keeps the creation time and whether the Error was logged so far, without
+// sharing later logs.
+func (m *logMark) derive() *logMark {
+	if m == nil {
+		return newMark()
+	}
+	return &logMark{logged: atomic.LoadUint32(&m.logged), created: m.created}
+}
+
+func newMark() *logMark {
+	if current().latency == nil {
+		return &logMark{}
+	}
+	return &logMark{created: time.Now()}
+}
+
+// loggedError marks errors of foreign types as logged.
+type loggedError struct {
+	error
+}
+
+func (le loggedError) Unwrap() error {
+	return le.error
+}
+
+func markLogged(err error) error {
+	if ee, ok := err.(Error); ok {
+		if ee.mark == nil {
+			ee.mark = newMark()
+		}
+		atomic.StoreUint32(&ee.mark.logged, 1)
+		return ee
+	}
+	if _, ok := err.(loggedError); ok {
+		return err
+	}
+	return loggedError{error: err}
+}
+
+// setLogged marks the logged instance: the first Error in the chain of err.
+// Inner layers and the children of joins are left alone, they may be shared
+// with errors that were not logged.
+func setLogged(err error) {
+	var ee Error
+	if asError(err, &ee) && ee.mark != nil {
+		atomic.StoreUint32(&ee.mark.logged, 1)
+	}
+}
+
+// SetLogged marks err as logged, for the Relog policy, when it was written by
+// other means than Log, such as an access log entry.
+func SetLogged(err error) {
+	setLogged(err)
+}
+
+// isLogged reports whether the first Error in the chain of err, or a foreign
+// error marked by LogReturn in front of it, was logged.
+func isLogged(err error) bool {
+	logged := false
+	Walk(err, func(err error) bool {
+		if ee, ok := errorOf(err); ok {
+			logged = ee.mark != nil && atomic.LoadUint32(&ee.mark.logged) == 1
+			return false
+		}
+		_, logged = err.(loggedError)
+		return !logged
+	})
+	return logged
+}
+
+// LogReturn logs err and returns it marked as logged, so the common "log
+// here, bubble up anyway" pattern is one call. Errors already marked are
+// returned without being logged again, whatever the Relog policy. Errors of
+// other types than Error are wrapped to carry the mark, errors.Is and
+// errors.As see through the wrapper.
+func LogReturn(logger *zap.Logger, err error) error {
+	if err == nil {
+		return nil
+	}
+	if !isLogged(err) {
+		Log(logger, err)
+	}
+	return markLogged(err)
+}
+
+// LogWrap wraps err like Wrap, then logs and returns it like LogReturn.
+func LogWrap(logger *zap.Logger, err error, format string, a ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return LogReturn(logger, wrap(err, fmt.Sprintf(format, a...)))
+}
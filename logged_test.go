@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+)
+
+func TestLogReturnWrappedSentinel(t *testing.T) {
+	errBase := Errorf("record not found")
+	core, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+
+	first := LogReturn(logger, WithMessage(errBase, "loading user 1"))
+	second := LogReturn(logger, WithMessage(errBase, "loading order 2"))
+	_ = LogReturn(logger, first)
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	for i, want := range []string{first.Error(), second.Error()} {
+		if entries[i].Message != want {
+			t.Errorf("entry %d: got %q, want %q", i, entries[i].Message, want)
+		}
+	}
+}
+
+func TestRelogPolicies(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		policy RelogPolicy
+		levels []zapcore.Level
+	}{
+		{"always", RelogAlways, []zapcore.Level{zap.ErrorLevel, zap.ErrorLevel, zap.ErrorLevel, zap.ErrorLevel, zap.ErrorLevel}},
+		{"skip", RelogSkip, []zapcore.Level{zap.ErrorLevel, zap.ErrorLevel, zap.ErrorLevel}},
+		{"debug", RelogDebug, []zapcore.Level{zap.ErrorLevel, zap.ErrorLevel, zap.ErrorLevel, zap.DebugLevel, zap.DebugLevel}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer CurrentConfig().Apply()
+			Configure(Relog(tt.policy))
+			errBase := Errorf("record not found")
+			core, logs := observer.New(zap.DebugLevel)
+			logger := zap.New(core)
+
+			lower := WithMessage(errBase, "loading user 1")
+			Log(logger, lower)
+			Log(logger, WithMessage(errBase, "loading order 2"))
+			Log(logger, fmt.Errorf("unrelated: %w", WithMessage(errBase, "loading item 3")))
+			Log(logger, WithMessage(lower, "handling request"))
+			Log(logger, fmt.Errorf("handling request: %w", lower))
+
+			entries := logs.AllUntimed()
+			if len(entries) != len(tt.levels) {
+				t.Fatalf("got %d entries, want %d", len(entries), len(tt.levels))
+			}
+			for i, level := range tt.levels {
+				if entries[i].Level != level {
+					t.Errorf("entry %d %q: got level %v, want %v", i, entries[i].Message, entries[i].Level, level)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger binds the package helpers to a zap logger, so services do not have
+// to thread both around.
+type Logger struct {
+	logger *zap.Logger
+}
+
+// NewLogger binds the package helpers to logger.
+func NewLogger(logger *zap.Logger) Logger {
+	return Logger{logger: logger}
+}
+
+// Zap returns the underlying zap logger.
+func (l Logger) Zap() *zap.Logger {
+	return l.logger
+}
+
+// Errorf creates an Error, see Errorf.
+func (l Logger) Errorf(format string, a ...interface{}) Error {
+	return errorf(zapcore.ErrorLevel, 1, format, a...)
+}
+
+// Wrap wraps err with a message, see Wrap.
+func (l Logger) Wrap(err error, format string, a ...interface{}) error {
+	if err == nil && !current().wrapNil {
+		return nil
+	}
+	return wrap(err, fmt.Sprintf(format, a...))
+}
+
+// Log logs err, see Log.
+func (l Logger) Log(err error) {
+	Log(l.logger, err)
+}
+
+// LogReturn logs err and returns it marked as logged, see LogReturn.
+func (l Logger) LogReturn(err error) error {
+	return LogReturn(l.logger, err)
+}
+
+// LogWrap wraps, logs and returns err, see LogWrap.
+func (l Logger) LogWrap(err error, format string, a ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return LogReturn(l.logger, wrap(err, fmt.Sprintf(format, a...)))
+}
@@ -0,0 +1,38 @@
+package errors
+
+// MessageOrder decides how WithMessage combines new context with the message
+// of an Error it wraps.
+type MessageOrder int
+
+const (
+	// MessagePrepend puts the new context in front: "context: message".
+	MessagePrepend MessageOrder = iota
+	// MessageAppend puts the new context last: "message: context".
+	MessageAppend
+	// MessageStructured keeps the message untouched and records the contexts
+	// in the chain field, outermost first.
+	MessageStructured
+)
+
+// Messages sets how wrapping combines messages and the separator used to
+// join them.
+func Messages(order MessageOrder, separator string) Option {
+	return func(c *config) {
+		c.messageOrder = order
+		c.messageSeparator = separator
+	}
+}
+
+func (ee Error) withContext(message string) Error {
+	conf := current()
+	switch conf.messageOrder {
+	case MessageAppend:
+		ee.base = ee.base.WithText(ee.base.Message() + conf.messageSeparator + message)
+	case MessageStructured:
+		chain := make([]string, 0, len(ee.chain)+1)
+		ee.chain = append(append(chain, message), ee.chain...)
+	default:
+		ee.base = ee.base.WithText(message + conf.messageSeparator + ee.base.Message())
+	}
+	return ee
+}
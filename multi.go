@@ -0,0 +1,123 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jpascal/zap-errors/core"
+	"go.uber.org/zap/zapcore"
+	"sort"
+)
+
+type child struct {
+	index int
+	key   string
+	err   error
+}
+
+// WrapAll collects the failures of a batch operation into one Error. errs is
+// expected to be aligned with the batch items, nil entries are successes and
+// are skipped; every failure keeps its index. WrapAll returns nil when no
+// item failed.
+func WrapAll(errs []error, format string, a ...interface{}) error {
+	children := make([]child, 0, len(errs))
+	for index, err := range errs {
+		if err != nil {
+			children = append(children, child{index: index, err: err})
+		}
+	}
+	if len(children) == 0 {
+		return nil
+	}
+	return multi(children, fmt.Sprintf(format, a...))
+}
+
+// WrapKeyed is WrapAll for batches addressed by key rather than position.
+func WrapKeyed(errs map[string]error, format string, a ...interface{}) error {
+	children := make([]child, 0, len(errs))
+	for key, err := range errs {
+		if err != nil {
+			children = append(children, child{index: -1, key: key, err: err})
+		}
+	}
+	if len(children) == 0 {
+		return nil
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].key < children[j].key
+	})
+	return multi(children, fmt.Sprintf(format, a...))
+}
+
+func multi(children []child, message string) Error {
+	statsCreated()
+	return Error{
+		base:       core.New(message, nil),
+		children:   children,
+		stacktrace: stackTraceAt(zapcore.ErrorLevel, 1),
+		mark:       newMark(),
+	}
+}
+
+// Errors returns the children of an error built by WrapAll or WrapKeyed.
+func (ee Error) Errors() []error {
+	errs := make([]error, 0, len(ee.children))
+	for _, c := range ee.children {
+		errs = append(errs, c.err)
+	}
+	return errs
+}
+
+// Is lets errors.Is find target among the children of an error built by
+// WrapAll or WrapKeyed. Unwrap returns a single error, so the standard
+// library does not see the children otherwise.
+func (ee Error) Is(target error) bool {
+	for _, c := range ee.children {
+		if errors.Is(c.err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As lets errors.As find the Error behind a *Error, as returned by Acquire,
+// and reach into the children of an error built by WrapAll or WrapKeyed.
+func (ee Error) As(target interface{}) bool {
+	if t, ok := target.(*Error); ok {
+		*t = ee
+		return true
+	}
+	for _, c := range ee.children {
+		if errors.As(c.err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c child) marshal(encoder zapcore.ObjectEncoder, conf *config) error {
+	if c.key != "" {
+		encoder.AddString(conf.keys.Key, c.key)
+	} else {
+		encoder.AddInt(conf.keys.Index, c.index)
+	}
+	var ee Error
+	if asError(c.err, &ee) {
+		return ee.marshal(encoder, conf)
+	}
+	encoder.AddString(conf.keys.Message, c.err.Error())
+	return nil
+}
+
+func marshalChildren(children []child, conf *config) zapcore.ArrayMarshaler {
+	return zapcore.ArrayMarshalerFunc(func(encoder zapcore.ArrayEncoder) error {
+		for _, c := range children {
+			c := c
+			if err := encoder.AppendObject(zapcore.ObjectMarshalerFunc(func(encoder zapcore.ObjectEncoder) error {
+				return c.marshal(encoder, conf)
+			})); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"errors"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestWrapAllChildrenReachable(t *testing.T) {
+	opErr := &net.OpError{Op: "dial", Err: errors.New("refused")}
+	batch := WrapAll([]error{nil, io.EOF, WithMessage(opErr, "item 2")}, "batch")
+	if !errors.Is(batch, io.EOF) {
+		t.Error("errors.Is does not find a child")
+	}
+	if errors.Is(batch, io.ErrUnexpectedEOF) {
+		t.Error("errors.Is finds an error that is not a child")
+	}
+	var found *net.OpError
+	if !errors.As(batch, &found) || found != opErr {
+		t.Errorf("errors.As found %v, want the error of a child", found)
+	}
+	keyed := WrapKeyed(map[string]error{"a": io.EOF}, "batch")
+	if !errors.Is(keyed, io.EOF) {
+		t.Error("errors.Is does not find a keyed child")
+	}
+}
+
+func TestAsFindsPooledError(t *testing.T) {
+	pooled := Acquire("pooled failure")
+	defer Release(pooled)
+	var ee Error
+	if !errors.As(error(pooled), &ee) || ee.Error() != "pooled failure" {
+		t.Errorf("errors.As found %v, want the pooled Error", ee)
+	}
+}
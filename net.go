@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"errors"
+	"net"
+)
+
+func init() {
+	causeEnrichers = append(causeEnrichers, enrichNet)
+}
+
+// enrichNet records the details of *net.OpError and *net.DNSError as net.*
+// and dns.* fields and classifies them as timeouts, unresolvable names or
+// unavailable peers.
+func enrichNet(ee Error, cause error) Error {
+	var opError *net.OpError
+	var dnsError *net.DNSError
+	isOp, isDNS := errors.As(cause, &opError), errors.As(cause, &dnsError)
+	if !isOp && !isDNS {
+		return ee
+	}
+	fields := map[string]interface{}{}
+	if isOp {
+		fields["net.op"] = opError.Op
+		fields["net.network"] = opError.Net
+		if opError.Addr != nil {
+			fields["net.address"] = opError.Addr.String()
+		}
+		if opError.Source != nil {
+			fields["net.source"] = opError.Source.String()
+		}
+	}
+	if isDNS {
+		fields["dns.name"] = dnsError.Name
+		if dnsError.Server != "" {
+			fields["dns.server"] = dnsError.Server
+		}
+		fields["dns.not_found"] = dnsError.IsNotFound
+	}
+	var netError net.Error
+	timeout := errors.As(cause, &netError) && netError.Timeout()
+	fields["net.timeout"] = timeout
+	var temporary interface{ Temporary() bool }
+	if errors.As(cause, &temporary) {
+		fields["net.temporary"] = temporary.Temporary()
+	}
+	ee = ee.WithFields(fields)
+	switch {
+	case timeout:
+		return ee.setKind(KindTimeout)
+	case isDNS && dnsError.IsNotFound:
+		return ee.setKind(KindNotFound)
+	}
+	return ee.setKind(KindUnavailable)
+}
@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"github.com/jpascal/zap-errors/core"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sync"
+	"sync/atomic"
+)
+
+type config struct {
+	stackOnEnrich    bool
+	wrapNil          bool
+	stats            StatsSink
+	audit            AuditSink
+	bytesEncoding    BytesEncoding
+	bytesLimit       int
+	schema           int
+	keys             Keys
+	stackLevel       zapcore.Level
+	levels           LevelPolicy
+	components       *ComponentPolicy
+	relog            RelogPolicy
+	debugArgs        bool
+	messageOrder     MessageOrder
+	messageSeparator string
+	eventPrefix      string
+	resolution       Resolution
+	recent           *Recent
+	strict           bool
+	latency          *LatencyHistogram
+	fatalLogger      *zap.Logger
+	crashDir         string
+	symbolizer       Symbolizer
+	rawPCs           PCMode
+	stackFormat      StackFormat
+	stackDepth       int
+	warnings         WarningPolicy
+	identities       IdentityPolicy
+	flags            FlagsFunc
+}
+
+var defaultConfig = config{
+	schema:           SchemaVersion,
+	keys:             defaultKeys,
+	stackLevel:       zapcore.DebugLevel,
+	messageSeparator: ": ",
+	eventPrefix:      "error.",
+	symbolizer:       runtimeSymbolizer{},
+	stackDepth:       10,
+}
+
+// settings holds the *config in effect. Configurations are never modified
+// once stored, changes store a modified copy.
+var (
+	settings   atomic.Value
+	settingsMu sync.Mutex
+)
+
+func init() {
+	settings.Store(&defaultConfig)
+}
+
+func current() *config {
+	return settings.Load().(*config)
+}
+
+// Option changes package-wide behavior, see Configure.
+type Option func(*config)
+
+// Configure applies options to the package configuration. The change is
+// atomic: concurrent errors see the configuration either before or after all
+// the options. It is meant to be called during program start-up, before
+// errors are created, see Config for changes made later on.
+func Configure(opts ...Option) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	changed := *current()
+	for _, opt := range opts {
+		opt(&changed)
+	}
+	store(&changed)
+}
+
+// store installs c, the caller holding settingsMu. The stack depth is shared
+// with the core package.
+func store(c *config) {
+	settings.Store(c)
+	core.SetStackDepth(c.stackDepth)
+}
+
+// StackDepth sets the number of frames captured for stacktraces, 10 by
+// default, including for the errors created by the core package.
+// Stacktraces of recovered panics have their own depth.
+func StackDepth(depth int) Option {
+	return func(c *config) {
+		if depth < 1 {
+			depth = 1
+		}
+		c.stackDepth = depth
+	}
+}
+
+// StackOnEnrich makes With* methods capture a stacktrace at the call site when
+// the error does not carry one yet.
+func StackOnEnrich(enabled bool) Option {
+	return func(c *config) {
+		c.stackOnEnrich = enabled
+	}
+}
+
+// WrapNil makes Wrap return an Error carrying only the message when it is
+// given a nil error, instead of returning nil.
+func WrapNil(enabled bool) Option {
+	return func(c *config) {
+		c.wrapNil = enabled
+	}
+}
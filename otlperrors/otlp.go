@@ -0,0 +1,115 @@
+// Package otlperrors exports the errors of github.com/jpascal/zap-errors as
+// OpenTelemetry log records, without depending on the OpenTelemetry SDK.
+package otlperrors
+
+import (
+	"context"
+	errors "github.com/jpascal/zap-errors"
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+// LogRecord holds what an OpenTelemetry log record needs, so errors reach the
+// collector pipeline without this package depending on the SDK. Attributes
+// follow the exception semantic conventions, the other error fields are
+// flattened under "error.".
+type LogRecord struct {
+	Timestamp      time.Time
+	SeverityNumber int
+	SeverityText   string
+	Body           string
+	TraceID        [16]byte
+	SpanID         [8]byte
+	Attributes     map[string]interface{}
+}
+
+// LogExporter is implemented by adapters of OTel SDK log exporters.
+type LogExporter interface {
+	Export(ctx context.Context, records []LogRecord) error
+}
+
+// SpanContextFunc returns the ids of the span active in ctx, typically:
+//
+//	func(ctx context.Context) ([16]byte, [8]byte) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		return sc.TraceID(), sc.SpanID()
+//	}
+type SpanContextFunc func(ctx context.Context) (traceID [16]byte, spanID [8]byte)
+
+// ToLogRecord converts err. spanContext may be nil.
+func ToLogRecord(ctx context.Context, err error, spanContext SpanContextFunc) LogRecord {
+	return logRecord(ctx, err, errors.LevelOf(err), spanContext)
+}
+
+func logRecord(ctx context.Context, err error, level zapcore.Level, spanContext SpanContextFunc) LogRecord {
+	record := LogRecord{
+		Timestamp:      time.Now(),
+		SeverityNumber: severityNumber(level),
+		SeverityText:   level.CapitalString(),
+		Body:           err.Error(),
+		Attributes: map[string]interface{}{
+			"exception.message": err.Error(),
+		},
+	}
+	if spanContext != nil {
+		record.TraceID, record.SpanID = spanContext(ctx)
+	}
+	if errType := errors.CauseType(err); errType != "" {
+		record.Attributes["exception.type"] = errType
+	}
+	object := errors.ZerologDict(err)
+	keys := errors.CurrentConfig().Keys()
+	if stack, ok := object[keys.Stacktrace].(string); ok {
+		record.Attributes["exception.stacktrace"] = stack
+		delete(object, keys.Stacktrace)
+	}
+	delete(object, keys.Message)
+	flatten("error.", object, record.Attributes)
+	return record
+}
+
+// severityNumber maps zap levels to OTel severity numbers.
+func severityNumber(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 5
+	case zapcore.InfoLevel:
+		return 9
+	case zapcore.WarnLevel:
+		return 13
+	case zapcore.ErrorLevel:
+		return 17
+	case zapcore.DPanicLevel:
+		return 18
+	case zapcore.PanicLevel:
+		return 19
+	default:
+		return 21
+	}
+}
+
+// flatten copies object into flat, nested objects having their keys joined
+// with dots.
+func flatten(prefix string, object map[string]interface{}, flat map[string]interface{}) {
+	for key, value := range object {
+		if nested, ok := value.(map[string]interface{}); ok {
+			flatten(prefix+key+".", nested, flat)
+			continue
+		}
+		flat[prefix+key] = value
+	}
+}
+
+// Exporter logs errors as OTel log records, following the Relog policy like
+// errors.Log does.
+type Exporter struct {
+	Exporter    LogExporter
+	SpanContext SpanContextFunc
+}
+
+// Log exports err, correlated with the span active in ctx.
+func (e Exporter) Log(ctx context.Context, err error) error {
+	return errors.LogWith(err, func(level zapcore.Level) error {
+		return e.Exporter.Export(ctx, []LogRecord{logRecord(ctx, err, level, e.SpanContext)})
+	})
+}
@@ -0,0 +1,116 @@
+package errors
+
+import (
+	"fmt"
+	"github.com/jpascal/zap-errors/core"
+	"go.uber.org/zap/zapcore"
+	"runtime"
+	"strings"
+)
+
+const panicStackDepth = 32
+
+// FromPanic converts a value obtained from recover into an Error. The value
+// is retained, see PanicValue, errors keep being reachable through the chain,
+// and the stacktrace starts at the panicking call when FromPanic is called from the
+// deferred function that recovered. The frames of the deferred functions
+// are logged apart as recovered_at, next to the header of the panicking
+// goroutine. Logged errors carry origin: panic.
+func FromPanic(value interface{}) Error {
+	statsCreated()
+	stacktrace, recoveredAt := panicStackTrace()
+	ee := Error{
+		base:        core.New(fmt.Sprintf("panic: %v", value), nil),
+		stacktrace:  stacktrace,
+		recoveredAt: recoveredAt,
+		goroutine:   goroutineHeader(),
+		mark:        newMark(),
+		panicked:    true,
+		panicValue:  value,
+	}
+	if err, ok := value.(error); ok {
+		ee.base = ee.base.WithCause(err)
+	}
+	return ee
+}
+
+// Recover converts a panic into an Error stored in *err. It must be deferred
+// directly:
+//
+//	defer errors.Recover(&err)
+func Recover(err *error) {
+	if value := recover(); value != nil {
+		*err = FromPanic(value)
+	}
+}
+
+// IsPanic reports whether err originates from a recovered panic.
+func IsPanic(err error) bool {
+	panicked := false
+	Walk(err, func(err error) bool {
+		if ee, ok := errorOf(err); ok {
+			panicked = ee.panicked
+		}
+		return !panicked
+	})
+	return panicked
+}
+
+// PanicValue returns the value of the recovered panic err originates from,
+// as given to panic, so recovery layers can panic again with it or single
+// out sentinels such as http.ErrAbortHandler.
+func PanicValue(err error) (interface{}, bool) {
+	var value interface{}
+	found := false
+	Walk(err, func(err error) bool {
+		if ee, ok := errorOf(err); ok && ee.panicked {
+			value, found = ee.panicValue, true
+		}
+		return !found
+	})
+	return value, found
+}
+
+// panicStackTrace returns the frames of the panicking call and the frames of
+// the deferred functions between runtime.gopanic and the caller of
+// FromPanic. Outside of a panic, all the frames are returned as the first.
+func panicStackTrace() ([]*runtime.Frame, []*runtime.Frame) {
+	if !stackEnabled(zapcore.ErrorLevel) {
+		return nil, nil
+	}
+	var pc [panicStackDepth]uintptr
+	n := runtime.Callers(3, pc[:])
+	traceFrames := make([]*runtime.Frame, 0, n)
+	for _, pc := range pc[:n] {
+		traceFrames = append(traceFrames, frameCache.frames(pc)...)
+	}
+	for i, frame := range traceFrames {
+		if frame.Function == "runtime.gopanic" {
+			return traceFrames[i+1:], userFrames(traceFrames[:i])
+		}
+	}
+	return traceFrames, nil
+}
+
+// userFrames returns the frames not belonging to this package, such as
+// Recover.
+func userFrames(frames []*runtime.Frame) []*runtime.Frame {
+	var user []*runtime.Frame
+	for _, frame := range frames {
+		if !strings.HasPrefix(frame.Function, packagePath+".") {
+			user = append(user, frame)
+		}
+	}
+	return user
+}
+
+// goroutineHeader returns the header of the current goroutine in stack
+// dumps, such as "goroutine 7 [running]".
+func goroutineHeader() string {
+	var buffer [64]byte
+	header := string(buffer[:runtime.Stack(buffer[:], false)])
+	if i := strings.IndexByte(header, '\n'); i >= 0 {
+		header = header[:i]
+	}
+	return strings.TrimSuffix(header, ":")
+}
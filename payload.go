@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"go.uber.org/zap/zapcore"
+)
+
+// BytesEncoding selects how []byte payloads are rendered.
+type BytesEncoding int
+
+const (
+	// BytesReflected leaves []byte payloads to the encoder's reflection.
+	BytesReflected BytesEncoding = iota
+	BytesHex
+	BytesBase64
+	// BytesPreview renders the payload as a quoted string with non-printable
+	// bytes escaped.
+	BytesPreview
+)
+
+// PayloadBytes sets how []byte payloads are rendered. When limit is positive
+// only the first limit bytes are rendered and the full size is logged as
+// payload_size.
+func PayloadBytes(encoding BytesEncoding, limit int) Option {
+	return func(c *config) {
+		c.bytesEncoding = encoding
+		c.bytesLimit = limit
+	}
+}
+
+func addPayload(encoder zapcore.ObjectEncoder, payload interface{}, conf *config) error {
+	data, ok := payload.([]byte)
+	if !ok || conf.bytesEncoding == BytesReflected {
+		return encoder.AddReflected(conf.keys.Payload, payload)
+	}
+	if conf.bytesLimit > 0 && len(data) > conf.bytesLimit {
+		encoder.AddInt(conf.keys.PayloadSize, len(data))
+		data = data[:conf.bytesLimit]
+	}
+	switch conf.bytesEncoding {
+	case BytesHex:
+		encoder.AddString(conf.keys.Payload, hex.EncodeToString(data))
+	case BytesBase64:
+		encoder.AddString(conf.keys.Payload, base64.StdEncoding.EncodeToString(data))
+	case BytesPreview:
+		encoder.AddString(conf.keys.Payload, fmt.Sprintf("%q", data))
+	default:
+		return fmt.Errorf("unknown bytes encoding %d", conf.bytesEncoding)
+	}
+	return nil
+}
+
+// PayloadAs returns the first payload of type T found in the chain of err,
+// see Walk.
+func PayloadAs[T any](err error) (T, bool) {
+	var payload T
+	found := false
+	Walk(err, func(err error) bool {
+		if ee, ok := errorOf(err); ok {
+			payload, found = ee.base.Payload().(T)
+		}
+		return !found
+	})
+	return payload, found
+}
@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"debug/elf"
+	"debug/gosym"
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"reflect"
+	"runtime"
+)
+
+// PCMode decides whether stacktraces are logged as resolved frames, raw
+// program counters or both.
+type PCMode int
+
+const (
+	// PCsOff logs resolved frames only.
+	PCsOff PCMode = iota
+	// PCsAlongside logs the program counters next to the resolved frames.
+	PCsAlongside
+	// PCsOnly logs the program counters instead of the resolved frames.
+	PCsOnly
+)
+
+// RawPCs sets whether the program counters of stacktraces are logged, one
+// per frame, under the pcs key, for trimmed or stripped production builds.
+// They are logged as offsets from a function of this package, so they do not
+// depend on where the binary was loaded, and are resolved later with
+// Symbolize against the same binary.
+func RawPCs(mode PCMode) Option {
+	return func(c *config) {
+		c.rawPCs = mode
+	}
+}
+
+// pcAnchor is the function program counters are made relative to.
+func pcAnchor() {}
+
+const pcAnchorName = packagePath + ".pcAnchor"
+
+var pcBase = reflect.ValueOf(pcAnchor).Pointer()
+
+func pcOffsets(frames []*runtime.Frame) zapcore.ArrayMarshaler {
+	return zapcore.ArrayMarshalerFunc(func(encoder zapcore.ArrayEncoder) error {
+		for _, frame := range frames {
+			encoder.AppendInt64(int64(frame.PC) - int64(pcBase))
+		}
+		return nil
+	})
+}
+
+// Symbolize resolves program counter offsets logged with RawPCs against the
+// ELF binary that logged them. Inlined calls are not expanded: their frame
+// names the function they were inlined into, with the position of the
+// inlined code.
+func Symbolize(binary string, offsets []int64) ([]runtime.Frame, error) {
+	file, err := elf.Open(binary)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	pclntab := file.Section(".gopclntab")
+	text := file.Section(".text")
+	if pclntab == nil || text == nil {
+		return nil, fmt.Errorf("%s: no Go symbol table", binary)
+	}
+	data, err := pclntab.Data()
+	if err != nil {
+		return nil, err
+	}
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(data, text.Addr))
+	if err != nil {
+		return nil, err
+	}
+	anchor := table.LookupFunc(pcAnchorName)
+	if anchor == nil {
+		return nil, fmt.Errorf("%s: %s not found", binary, pcAnchorName)
+	}
+	frames := make([]runtime.Frame, 0, len(offsets))
+	for _, offset := range offsets {
+		pc := uint64(int64(anchor.Entry) + offset)
+		// Program counters are return addresses, the call is the
+		// instruction before.
+		file, line, fn := table.PCToLine(pc - 1)
+		frame := runtime.Frame{PC: uintptr(pc), File: file, Line: line}
+		if fn != nil {
+			frame.Function = fn.Name
+			frame.Entry = uintptr(fn.Entry)
+		}
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
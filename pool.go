@@ -0,0 +1,49 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+var errorPool = sync.Pool{
+	New: func() interface{} {
+		return &Error{stacktrace: make([]*runtime.Frame, 0, 16)}
+	},
+}
+
+// Acquire is the pooled counterpart of Errorf for hot paths creating and
+// immediately logging large numbers of errors. The returned Error reuses the
+// frame slice of previously released errors.
+//
+// The caller owns the returned Error until it passes it to Release. After
+// Release neither the pointer nor plain copies of the Error (such as storing
+// it as an error value) may be used, as the frame slice is handed out again.
+// Errors derived with With* methods or wrapping own their frames and remain
+// valid. Errors that escape the function that acquired them must not be
+// released.
+func Acquire(format string, a ...interface{}) *Error {
+	statsCreated()
+	ee := errorPool.Get().(*Error)
+	ee.base = ee.base.WithText(fmt.Sprintf(format, a...))
+	ee.mark = newMark()
+	ee.stacktrace = appendStackTrace(ee.stacktrace[:0], 0)
+	ee.pooled = true
+	return ee
+}
+
+// Release returns an Error obtained from Acquire to the pool.
+func Release(ee *Error) {
+	if ee == nil {
+		return
+	}
+	*ee = Error{stacktrace: ee.stacktrace[:0]}
+	errorPool.Put(ee)
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"go.uber.org/zap"
+	"testing"
+)
+
+// pooledAllocs is the allocation budget of Acquire and Release: the message
+// and the log mark. Frames are not allocated.
+const pooledAllocs = 2
+
+func TestAcquireAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are not representative under the race detector")
+	}
+	pooled := testing.AllocsPerRun(1000, func() {
+		Release(Acquire("pooled failure"))
+	})
+	if pooled > pooledAllocs {
+		t.Errorf("Acquire and Release allocate %v times, budget is %d", pooled, pooledAllocs)
+	}
+	plain := testing.AllocsPerRun(1000, func() {
+		_ = Errorf("plain failure")
+	})
+	if pooled >= plain {
+		t.Errorf("Acquire allocates %v times, no less than Errorf with %v", pooled, plain)
+	}
+}
+
+func TestReleaseClearsReusedError(t *testing.T) {
+	first := Acquire("first failure")
+	*first = first.WithField("user", 1).WithPayload("payload").WithCode(7).WithKind(KindNotFound)
+	Log(zap.NewNop(), *first)
+	Release(first)
+
+	second := Acquire("second failure")
+	defer Release(second)
+	if second != first {
+		t.Skip("the pool did not hand the released error out again")
+	}
+	if second.fields != nil || second.base.Payload() != nil || second.base.Code() != 0 || second.kind != KindUnknown {
+		t.Errorf("reused error keeps state: %+v", *second)
+	}
+	if isLogged(*second) {
+		t.Error("reused error is marked as logged")
+	}
+	if second.Error() != "second failure" {
+		t.Errorf("got message %q", second.Error())
+	}
+	if len(second.stacktrace) == 0 || second.stacktrace[0].Function != packagePath+".TestReleaseClearsReusedError" {
+		t.Errorf("reused error has a stale stacktrace: %v", second.stacktrace)
+	}
+}
+
+func TestDerivedErrorsOwnPooledFrames(t *testing.T) {
+	pooled := Acquire("pooled failure")
+	derived := WithMessage(*pooled, "layer")
+	want := derived.stacktrace[0]
+	Release(pooled)
+	reused := acquireElsewhere()
+	defer Release(reused)
+	if derived.stacktrace[0] != want {
+		t.Errorf("derived error sees the frames of the reused error: %v", derived.stacktrace[0])
+	}
+
+	plain := Errorf("failure")
+	if shared := WithMessage(plain, "layer"); &shared.stacktrace[0] != &plain.stacktrace[0] {
+		t.Error("frames of an Error not pooled are copied")
+	}
+}
+
+func acquireElsewhere() *Error {
+	return Acquire("other failure")
+}
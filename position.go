@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type position struct {
+	file   string
+	line   int
+	column int
+	source []byte
+}
+
+// WithPosition records where in a file, typically a configuration file, the
+// error was found. The position is logged as the config.file, config.line
+// and config.column fields, lines and columns starting at 1, a zero column
+// meaning the whole line. When source holds the content of the file,
+// FormatText renders the offending line with a caret under the column.
+func (ee Error) WithPosition(file string, line, column int, source []byte) Error {
+	ee.position = &position{file: file, line: line, column: column, source: source}
+	fields := map[string]interface{}{"config.file": file, "config.line": line}
+	if column > 0 {
+		fields["config.column"] = column
+	}
+	return ee.WithFields(fields)
+}
+
+// writeExcerpt renders the location of p and, when its source is known, the
+// line before it and the offending line with a caret under the column:
+//
+//	--> config.yaml:3:7
+//	   2 | server:
+//	   3 |   port: eighty
+//	     |         ^
+func writeExcerpt(builder *strings.Builder, p *position, indent string) {
+	location := p.file + ":" + strconv.Itoa(p.line)
+	if p.column > 0 {
+		location += ":" + strconv.Itoa(p.column)
+	}
+	_, _ = fmt.Fprintf(builder, "%s--> %s\n", indent, location)
+	lines := bytes.Split(p.source, []byte("\n"))
+	if p.source == nil || p.line < 1 || p.line > len(lines) {
+		return
+	}
+	width := len(strconv.Itoa(p.line))
+	if p.line > 1 {
+		_, _ = fmt.Fprintf(builder, "%s %*d | %s\n", indent, width, p.line-1, bytes.TrimRight(lines[p.line-2], "\r"))
+	}
+	current := bytes.TrimRight(lines[p.line-1], "\r")
+	_, _ = fmt.Fprintf(builder, "%s %*d | %s\n", indent, width, p.line, current)
+	if p.column < 1 || p.column > len(current)+1 {
+		return
+	}
+	// Tabs are kept so the caret lines up with the rendered line.
+	padding := bytes.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+		return ' '
+	}, current[:p.column-1])
+	_, _ = fmt.Fprintf(builder, "%s %*s | %s^\n", indent, width, "", padding)
+}
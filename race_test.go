@@ -0,0 +1,7 @@
+//go:build race
+
+package errors
+
+// raceEnabled skips the allocation budgets, the race detector allocates on
+// its own.
+const raceEnabled = true
@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"sort"
+	"sync"
+)
+
+// CodeRange is a range of codes, bounds included, reserved by a subsystem.
+type CodeRange struct {
+	Min       int
+	Max       int
+	Subsystem string
+}
+
+var codeRanges struct {
+	mu     sync.RWMutex
+	ranges []CodeRange
+}
+
+// ReserveCodes reserves the codes min to max, both included, for subsystem.
+// It fails when the range overlaps a range reserved before. Logged errors
+// with a code in the range are annotated with the subsystem name.
+func ReserveCodes(min, max int, subsystem string) error {
+	if min > max {
+		return Errorf("invalid code range %d-%d for %s", min, max, subsystem).WithKind(KindInvalid)
+	}
+	codeRanges.mu.Lock()
+	defer codeRanges.mu.Unlock()
+	for _, r := range codeRanges.ranges {
+		if min <= r.Max && r.Min <= max {
+			return Errorf("code range %d-%d for %s collides with %d-%d reserved by %s",
+				min, max, subsystem, r.Min, r.Max, r.Subsystem).WithKind(KindConflict)
+		}
+	}
+	codeRanges.ranges = append(codeRanges.ranges, CodeRange{Min: min, Max: max, Subsystem: subsystem})
+	sort.Slice(codeRanges.ranges, func(i, j int) bool {
+		return codeRanges.ranges[i].Min < codeRanges.ranges[j].Min
+	})
+	return nil
+}
+
+// MustReserveCodes is ReserveCodes panicking on collisions, for use in init.
+func MustReserveCodes(min, max int, subsystem string) {
+	if err := ReserveCodes(min, max, subsystem); err != nil {
+		panic(err)
+	}
+}
+
+// CodeRanges returns the reserved ranges ordered by their lower bound.
+func CodeRanges() []CodeRange {
+	codeRanges.mu.RLock()
+	defer codeRanges.mu.RUnlock()
+	return append([]CodeRange(nil), codeRanges.ranges...)
+}
+
+// SubsystemOf returns the subsystem that reserved code.
+func SubsystemOf(code int) (string, bool) {
+	codeRanges.mu.RLock()
+	defer codeRanges.mu.RUnlock()
+	index := sort.Search(len(codeRanges.ranges), func(i int) bool {
+		return codeRanges.ranges[i].Max >= code
+	})
+	if index < len(codeRanges.ranges) && codeRanges.ranges[index].Min <= code {
+		return codeRanges.ranges[index].Subsystem, true
+	}
+	return "", false
+}
@@ -0,0 +1,130 @@
+package errors
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Recent keeps the last errors in a ring buffer and serves them over HTTP,
+// to inspect the recent failures of an instance without going through the
+// central log system:
+//
+//	recent := errors.NewRecent(100)
+//	errors.Configure(errors.KeepRecent(recent))
+//	http.Handle("/debug/errors", recent)
+type Recent struct {
+	mu      sync.Mutex
+	entries []recentEntry
+	next    int
+	full    bool
+}
+
+type recentEntry struct {
+	time time.Time
+	err  error
+}
+
+// NewRecent creates a Recent keeping the last size errors.
+func NewRecent(size int) *Recent {
+	if size < 1 {
+		size = 1
+	}
+	return &Recent{entries: make([]recentEntry, size)}
+}
+
+// KeepRecent makes Log record the errors it writes in recent.
+func KeepRecent(recent *Recent) Option {
+	return func(c *config) {
+		c.recent = recent
+	}
+}
+
+func recordRecent(err error) {
+	if recent := current().recent; recent != nil {
+		recent.Record(err)
+	}
+}
+
+// Record adds err, evicting the oldest error when the buffer is full.
+func (r *Recent) Record(err error) {
+	if err == nil {
+		return
+	}
+	r.mu.Lock()
+	r.entries[r.next] = recentEntry{time: time.Now(), err: err}
+	r.next = (r.next + 1) % len(r.entries)
+	r.full = r.full || r.next == 0
+	r.mu.Unlock()
+}
+
+// snapshot returns the recorded errors, most recent first.
+func (r *Recent) snapshot() []recentEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := r.next
+	if r.full {
+		n = len(r.entries)
+	}
+	entries := make([]recentEntry, 0, n)
+	for i := 1; i <= n; i++ {
+		entries = append(entries, r.entries[(r.next-i+len(r.entries))%len(r.entries)])
+	}
+	return entries
+}
+
+// ServeHTTP renders the recorded errors, most recent first, as HTML for
+// browsers and as JSON otherwise or when the format query parameter is json.
+func (r *Recent) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	entries := r.snapshot()
+	if req.URL.Query().Get("format") != "json" && strings.Contains(req.Header.Get("Accept"), "text/html") {
+		r.serveHTML(w, entries)
+		return
+	}
+	documents := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		documents = append(documents, map[string]interface{}{
+			"time":  entry.time,
+			"level": levelOf(entry.err).String(),
+			"error": ZerologDict(entry.err),
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(documents)
+}
+
+var recentTemplate = template.Must(template.New("recent").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Recent errors</title></head>
+<body>
+<h1>Recent errors</h1>
+<table border="1" cellpadding="4">
+<tr><th>Time</th><th>Level</th><th>Error</th></tr>
+{{range .}}<tr><td>{{.Time}}</td><td>{{.Level}}</td><td><pre>{{.Text}}</pre></td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func (r *Recent) serveHTML(w http.ResponseWriter, entries []recentEntry) {
+	type row struct {
+		Time  string
+		Level string
+		Text  string
+	}
+	rows := make([]row, 0, len(entries))
+	for _, entry := range entries {
+		rows = append(rows, row{
+			Time:  entry.time.Format(time.RFC3339Nano),
+			Level: levelOf(entry.err).String(),
+			Text:  FormatText(entry.err, TextOptions{}),
+		})
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = recentTemplate.Execute(w, rows)
+}
@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"errors"
+	"go.uber.org/zap"
+	"net/http"
+	"syscall"
+)
+
+// DisconnectPolicy decides how Recovery handles panics caused by the client
+// going away, see IsDisconnect.
+type DisconnectPolicy int
+
+const (
+	// DisconnectDebug logs a single debug entry without a panic report.
+	DisconnectDebug DisconnectPolicy = iota
+	// DisconnectSkip logs nothing.
+	DisconnectSkip
+	// DisconnectReport reports the panic like any other.
+	DisconnectReport
+)
+
+// IsDisconnect reports whether err, or the panic it originates from, is
+// http.ErrAbortHandler or a broken pipe or connection reset, the failures of
+// writing to a client that went away rather than bugs.
+func IsDisconnect(err error) bool {
+	return errors.Is(err, http.ErrAbortHandler) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// Recovery is a middleware turning the panics of handlers into Errors, see
+// FromPanic, answered with a 500 response. The error is recorded for
+// httperrors.AccessLog when it wraps Recovery, and logged with logger
+// otherwise.
+// Disconnects, see IsDisconnect, are handled per policy and then abort the
+// response with http.ErrAbortHandler, which net/http does not report.
+func Recovery(logger *zap.Logger, policy DisconnectPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				value := recover()
+				if value == nil {
+					return
+				}
+				if cause, ok := value.(error); ok && IsDisconnect(cause) {
+					switch policy {
+					case DisconnectDebug:
+						logger.Debug("client disconnected",
+							zap.String("method", r.Method),
+							zap.String("path", r.URL.Path),
+							zap.String("cause", cause.Error()),
+						)
+					case DisconnectReport:
+						Log(logger, FromPanic(value))
+					}
+					panic(http.ErrAbortHandler)
+				}
+				err := FromPanic(value)
+				if _, ok := r.Context().Value(errorSlotKey{}).(*errorSlot); ok {
+					RecordError(r, err)
+				} else {
+					Log(logger, err)
+				}
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Report is a self-contained snapshot of an error and the process it
+// happened in, meant to be written to a crash dump file on fatal exits.
+type Report struct {
+	Time   time.Time     `json:"time"`
+	Level  string        `json:"level"`
+	Errors []ReportError `json:"errors"`
+	Env    ReportEnv     `json:"env"`
+}
+
+// ReportError describes one error of the chain, in the order of Walk.
+type ReportError struct {
+	Message    string                 `json:"message"`
+	Type       string                 `json:"type"`
+	Code       int                    `json:"code,omitempty"`
+	Kind       Kind                   `json:"kind,omitempty"`
+	Stacktrace string                 `json:"stacktrace,omitempty"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+	Payload    interface{}            `json:"payload,omitempty"`
+}
+
+// ReportEnv describes the process.
+type ReportEnv struct {
+	Hostname   string `json:"hostname,omitempty"`
+	PID        int    `json:"pid"`
+	Executable string `json:"executable,omitempty"`
+	Module     string `json:"module,omitempty"`
+	Version    string `json:"version,omitempty"`
+	GoVersion  string `json:"go_version"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+	Goroutines int    `json:"goroutines"`
+}
+
+// Snapshot captures err with the current process metadata.
+func Snapshot(err error) Report {
+	report := Report{Time: time.Now(), Level: levelOf(err).String(), Env: snapshotEnv()}
+	conf := current()
+	Walk(err, func(err error) bool {
+		item := ReportError{Message: err.Error(), Type: fmt.Sprintf("%T", err)}
+		if ee, ok := errorOf(err); ok {
+			item.Code = resolveCode(err, conf)
+			item.Kind = ee.kind
+			item.Fields = ee.fields
+			item.Payload = resolvePayload(err, conf)
+			if len(ee.stacktrace) > 0 {
+				var buffer bytes.Buffer
+				writeDefaultStack(&buffer, ee.stacktrace, ee.args)
+				item.Stacktrace = buffer.String()
+			}
+		}
+		report.Errors = append(report.Errors, item)
+		return true
+	})
+	return report
+}
+
+func snapshotEnv() ReportEnv {
+	env := ReportEnv{
+		PID:        os.Getpid(),
+		GoVersion:  runtime.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Goroutines: runtime.NumGoroutine(),
+	}
+	env.Hostname, _ = os.Hostname()
+	env.Executable, _ = os.Executable()
+	if info, ok := debug.ReadBuildInfo(); ok {
+		env.Module = info.Main.Path
+		env.Version = info.Main.Version
+	}
+	return env
+}
+
+// WriteTo writes the report as indented JSON.
+func (r Report) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// WriteText writes the report as human readable text.
+func (r Report) WriteText(w io.Writer) (int64, error) {
+	builder := &strings.Builder{}
+	_, _ = fmt.Fprintf(builder, "%s %s\n", r.Time.Format(time.RFC3339Nano), r.Level)
+	for i, item := range r.Errors {
+		_, _ = fmt.Fprintf(builder, "\n[%d] %s: %s\n", i, item.Type, item.Message)
+		if item.Code != 0 {
+			_, _ = fmt.Fprintf(builder, "  code: %d\n", item.Code)
+		}
+		if item.Kind != KindUnknown {
+			_, _ = fmt.Fprintf(builder, "  kind: %s\n", item.Kind)
+		}
+		if len(item.Fields) > 0 {
+			builder.WriteString("  fields:")
+			writeYAMLish(builder, reflect.ValueOf(item.Fields), "    ")
+		}
+		if item.Payload != nil {
+			builder.WriteString("  payload:")
+			writeYAMLish(builder, reflect.ValueOf(item.Payload), "    ")
+		}
+		if item.Stacktrace != "" {
+			builder.WriteString("  stack:\n")
+			for _, line := range strings.Split(strings.TrimSuffix(item.Stacktrace, "\n"), "\n") {
+				if strings.HasSuffix(line, "\t") {
+					_, _ = fmt.Fprintf(builder, "    %s\n", strings.TrimSuffix(line, "\t"))
+				} else {
+					_, _ = fmt.Fprintf(builder, "        %s\n", strings.TrimPrefix(line, "\t"))
+				}
+			}
+		}
+	}
+	builder.WriteString("\nenv:")
+	writeYAMLish(builder, reflect.ValueOf(r.Env), "  ")
+	n, err := io.WriteString(w, builder.String())
+	return int64(n), err
+}
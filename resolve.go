@@ -0,0 +1,84 @@
+package errors
+
+import "errors"
+
+// Resolution decides which layer wins when several Errors of a chain set a
+// code or a payload, as when Errorf wraps an Error with %w.
+type Resolution int
+
+const (
+	// ResolveOutermost uses the value set by the outermost layer.
+	ResolveOutermost Resolution = iota
+	// ResolveInnermost uses the value set by the innermost layer, closest to
+	// the failure.
+	ResolveInnermost
+	// ResolveAll collects the payloads of every layer, outermost first, in a
+	// []interface{}. Codes resolve like ResolveOutermost.
+	ResolveAll
+)
+
+// Resolve sets the policy used by CodeOf, PayloadOf and the logged error
+// object.
+func Resolve(policy Resolution) Option {
+	return func(c *config) {
+		c.resolution = policy
+	}
+}
+
+// CodeOf returns the code of err resolved by the Resolve policy, or 0.
+func CodeOf(err error) int {
+	return resolveCode(err, current())
+}
+
+// PayloadOf returns the payload of err resolved by the Resolve policy, or
+// nil.
+func PayloadOf(err error) interface{} {
+	return resolvePayload(err, current())
+}
+
+// eachLayer calls fn with the Errors found unwrapping err, outermost first,
+// until fn returns false. Unlike Walk it does not branch into joins: joined
+// errors are not layers.
+func eachLayer(err error, fn func(ee Error) bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if ee, ok := errorOf(err); ok && !fn(ee) {
+			return
+		}
+	}
+}
+
+func resolveCode(err error, conf *config) int {
+	code := 0
+	eachLayer(err, func(ee Error) bool {
+		if ee.base.Code() != 0 {
+			code = ee.base.Code()
+			return conf.resolution == ResolveInnermost
+		}
+		return true
+	})
+	return code
+}
+
+func resolvePayload(err error, conf *config) interface{} {
+	var payload interface{}
+	var payloads []interface{}
+	eachLayer(err, func(ee Error) bool {
+		if ee.base.Payload() == nil {
+			return true
+		}
+		switch conf.resolution {
+		case ResolveInnermost:
+			payload = ee.base.Payload()
+			return true
+		case ResolveAll:
+			payloads = append(payloads, ee.base.Payload())
+			return true
+		}
+		payload = ee.base.Payload()
+		return false
+	})
+	if payloads != nil {
+		return payloads
+	}
+	return payload
+}
@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type codeStats struct {
+	codes []int
+}
+
+func (s *codeStats) Created() {}
+
+func (s *codeStats) Logged(code int, kind Kind) {
+	s.codes = append(s.codes, code)
+}
+
+func TestResolutionUsedConsistently(t *testing.T) {
+	defer CurrentConfig().Apply()
+	stats := &codeStats{}
+	Configure(
+		Resolve(ResolveInnermost),
+		Stats(stats),
+		Levels(LevelPolicy{Codes: map[int]zapcore.Level{404: zapcore.WarnLevel}}),
+	)
+	// Errorf wraps with %w like fmt.Errorf, vet only knows about the latter.
+	wrapping := "outer: %w"
+	err := Errorf(wrapping, Errorf("inner").WithCode(404)).WithCode(500)
+
+	core, logs := observer.New(zap.DebugLevel)
+	Log(zap.New(core), err)
+	entry := logs.AllUntimed()[0]
+	if code := entry.ContextMap()["error"].(map[string]interface{})["code"]; code != 404 {
+		t.Errorf("logged code %v, want 404", code)
+	}
+	if entry.Level != zapcore.WarnLevel {
+		t.Errorf("logged at %v, want the level of the resolved code", entry.Level)
+	}
+	if len(stats.codes) != 1 || stats.codes[0] != 404 {
+		t.Errorf("stats got codes %v, want [404]", stats.codes)
+	}
+	if status := StatusOf(err); status != 404 {
+		t.Errorf("got status %d, want 404", status)
+	}
+	if !IsClientError(err) {
+		t.Error("not a client error")
+	}
+	found := false
+	for _, header := range EncodeHeaders(err) {
+		if header.Key == HeaderCode {
+			found = string(header.Value) == strconv.Itoa(404)
+		}
+	}
+	if !found {
+		t.Errorf("headers %v do not carry code 404", EncodeHeaders(err))
+	}
+	if text := FormatText(err, TextOptions{NoStack: true}); !strings.Contains(text, "code: 404") {
+		t.Errorf("text does not show code 404:\n%s", text)
+	}
+	if details := ToApplicationError(err).Details; details.Code != 404 {
+		t.Errorf("got application error code %d, want 404", details.Code)
+	}
+}
@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"errors"
+	"github.com/jpascal/zap-errors/core"
+	"reflect"
+)
+
+// noiseTypes are the types of plain and wrapping errors of the standard
+// library, which say nothing about the cause.
+var noiseTypes = map[string]bool{
+	"*errors.errorString": true,
+	"*fmt.wrapError":      true,
+	"*fmt.wrapErrors":     true,
+}
+
+// CauseType names the dynamic type of the first error in the chain of err
+// that is not a mere wrapper, logged as the type of the error.
+func CauseType(err error) string {
+	return causeType(err)
+}
+
+// causeType names the dynamic type of the first error in the chain of err
+// that is not a mere wrapper, such as *net.OpError for network failures.
+func causeType(err error) string {
+	for err != nil {
+		switch err.(type) {
+		case Error, *Error, core.Error, loggedError:
+		default:
+			// Named like %T does, without formatting.
+			if name := reflect.TypeOf(err).String(); !noiseTypes[name] {
+				return name
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return ""
+}
+
+// RootCause returns the innermost error of the chain of err, the one that
+// wraps nothing, for alerting on the original failure rather than on the
+// decorated message. At join points, see Walk, it follows the first branch;
+// RootCauses returns the roots of every branch.
+func RootCause(err error) error {
+	var root error
+	roots(err, func(err error) bool {
+		root = err
+		return false
+	})
+	return root
+}
+
+// RootCauses returns the roots of every branch of the chain of err, in the
+// order Walk visits them.
+func RootCauses(err error) []error {
+	var found []error
+	roots(err, func(err error) bool {
+		found = append(found, err)
+		return true
+	})
+	return found
+}
+
+// RootMessage returns the message of RootCause, or "" when err is nil.
+func RootMessage(err error) string {
+	if root := RootCause(err); root != nil {
+		return root.Error()
+	}
+	return ""
+}
+
+// roots calls fn for the errors of the chain of err that have no cause until
+// fn returns false.
+func roots(err error, fn func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+	leaf := true
+	for _, cause := range causes(err) {
+		if cause == nil {
+			continue
+		}
+		leaf = false
+		if !roots(cause, fn) {
+			return false
+		}
+	}
+	if leaf {
+		return fn(err)
+	}
+	return true
+}
+
+// causes returns the errors err wraps, the branches Walk follows.
+func causes(err error) []error {
+	switch e := err.(type) {
+	case Error:
+		found := make([]error, 0, len(e.children)+1)
+		for _, c := range e.children {
+			found = append(found, c.err)
+		}
+		return append(found, e.base.Unwrap())
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+	case interface{ Unwrap() error }:
+		return []error{e.Unwrap()}
+	}
+	return nil
+}
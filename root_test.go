@@ -0,0 +1,25 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestCauseTypeSkipsNoise(t *testing.T) {
+	opErr := &net.OpError{Op: "dial", Err: errors.New("refused")}
+	for _, tt := range []struct {
+		err  error
+		want string
+	}{
+		{Errorf("failure"), ""},
+		{WithMessage(errors.New("refused"), "dial"), ""},
+		{fmt.Errorf("dialing: %w", opErr), "*net.OpError"},
+		{WithMessage(opErr, "dialing"), "*net.OpError"},
+	} {
+		if got := causeType(tt.err); got != tt.want {
+			t.Errorf("%v: got type %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package errors
+
+import "encoding/json"
+
+// SchemaVersion is the version of the logged error object layout emitted by
+// default. Version 1 is the layout used before versioning was introduced: it
+// has no schema and code fields. Version 2 adds them and is frozen, fields
+// added since are logged with version 3 only.
+const SchemaVersion = 3
+
+// Keys names the fields of the logged error object.
+type Keys struct {
+	Schema      string
+	Message     string
+	Chain       string
+	Code        string
+	Subsystem   string
+	Kind        string
+	Tenant      string
+	User        string
+	Retryable   string
+	Origin      string
+	Goroutine   string
+	Class       string
+	Type        string
+	GroupID     string
+	Cached      string
+	Age         string
+	Shared      string
+	Leader      string
+	Stacktrace  string
+	RecoveredAt string
+	PCs         string
+	Component   string
+	Payload     string
+	PayloadSize string
+	Errors      string
+	Index       string
+	Key         string
+}
+
+var defaultKeys = Keys{
+	Schema:      "schema",
+	Message:     "message",
+	Chain:       "chain",
+	Code:        "code",
+	Subsystem:   "subsystem",
+	Kind:        "kind",
+	Tenant:      "tenant",
+	User:        "user",
+	Retryable:   "retryable",
+	Origin:      "origin",
+	Goroutine:   "goroutine",
+	Class:       "class",
+	Type:        "type",
+	GroupID:     "group_id",
+	Cached:      "cached",
+	Age:         "age",
+	Shared:      "shared",
+	Leader:      "leader",
+	Stacktrace:  "stacktrace",
+	RecoveredAt: "recovered_at",
+	PCs:         "pcs",
+	Component:   "component",
+	Payload:     "payload",
+	PayloadSize: "payload_size",
+	Errors:      "errors",
+	Index:       "index",
+	Key:         "key",
+}
+
+// Schema selects the layout version of the logged error object, so log
+// pipelines can migrate at their own pace. Versions before 3 leave out the
+// fields added since, such as class, tenant, chain and children.
+func Schema(version int) Option {
+	return func(c *config) {
+		c.schema = version
+	}
+}
+
+// SchemaKeys renames fields of the logged error object. Empty names keep the
+// default.
+func SchemaKeys(keys Keys) Option {
+	return func(c *config) {
+		c.keys = mergeKeys(defaultKeys, keys)
+	}
+}
+
+func mergeKeys(base, override Keys) Keys {
+	pick := func(base, override string) string {
+		if override != "" {
+			return override
+		}
+		return base
+	}
+	return Keys{
+		Schema:      pick(base.Schema, override.Schema),
+		Message:     pick(base.Message, override.Message),
+		Chain:       pick(base.Chain, override.Chain),
+		Code:        pick(base.Code, override.Code),
+		Subsystem:   pick(base.Subsystem, override.Subsystem),
+		Kind:        pick(base.Kind, override.Kind),
+		Tenant:      pick(base.Tenant, override.Tenant),
+		User:        pick(base.User, override.User),
+		Retryable:   pick(base.Retryable, override.Retryable),
+		Origin:      pick(base.Origin, override.Origin),
+		Goroutine:   pick(base.Goroutine, override.Goroutine),
+		Class:       pick(base.Class, override.Class),
+		Type:        pick(base.Type, override.Type),
+		GroupID:     pick(base.GroupID, override.GroupID),
+		Cached:      pick(base.Cached, override.Cached),
+		Age:         pick(base.Age, override.Age),
+		Shared:      pick(base.Shared, override.Shared),
+		Leader:      pick(base.Leader, override.Leader),
+		Stacktrace:  pick(base.Stacktrace, override.Stacktrace),
+		RecoveredAt: pick(base.RecoveredAt, override.RecoveredAt),
+		PCs:         pick(base.PCs, override.PCs),
+		Component:   pick(base.Component, override.Component),
+		Payload:     pick(base.Payload, override.Payload),
+		PayloadSize: pick(base.PayloadSize, override.PayloadSize),
+		Errors:      pick(base.Errors, override.Errors),
+		Index:       pick(base.Index, override.Index),
+		Key:         pick(base.Key, override.Key),
+	}
+}
+
+// JSONSchema describes the logged error object for the configured schema
+// version and keys as a JSON Schema document.
+func JSONSchema() ([]byte, error) {
+	conf := current()
+	keys := conf.keys
+	properties := map[string]interface{}{
+		keys.Message:     map[string]interface{}{"type": "string"},
+		keys.Kind:        map[string]interface{}{"type": "string"},
+		keys.Stacktrace:  map[string]interface{}{"type": "string"},
+		keys.Payload:     map[string]interface{}{},
+		keys.PayloadSize: map[string]interface{}{"type": "integer"},
+	}
+	if conf.schema >= 2 {
+		properties[keys.Schema] = map[string]interface{}{"const": conf.schema}
+		properties[keys.Code] = map[string]interface{}{"type": "integer"}
+	}
+	if conf.schema >= 3 {
+		properties[keys.Chain] = map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}
+		properties[keys.Subsystem] = map[string]interface{}{"type": "string"}
+		properties[keys.Tenant] = map[string]interface{}{"type": "string"}
+		properties[keys.User] = map[string]interface{}{"type": "string"}
+		properties[keys.Retryable] = map[string]interface{}{"type": "boolean"}
+		properties[keys.Origin] = map[string]interface{}{"enum": []string{"panic"}}
+		properties[keys.Goroutine] = map[string]interface{}{"type": "string"}
+		properties[keys.Class] = map[string]interface{}{
+			"enum": []string{ClassTimeout, ClassDependency, ClassValidation, ClassPanic, ClassDisconnect, ClassUnknown},
+		}
+		properties[keys.Type] = map[string]interface{}{"type": "string"}
+		properties[keys.GroupID] = map[string]interface{}{"type": "string"}
+		properties[keys.Cached] = map[string]interface{}{"type": "boolean"}
+		properties[keys.Age] = map[string]interface{}{}
+		properties[keys.Shared] = map[string]interface{}{"type": "boolean"}
+		properties[keys.Leader] = map[string]interface{}{"type": "string"}
+		properties[keys.RecoveredAt] = map[string]interface{}{"type": "string"}
+		properties[keys.PCs] = map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer"}}
+		properties[keys.Component] = map[string]interface{}{"type": "string"}
+		properties[keys.Errors] = map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"$ref": "#",
+				"properties": map[string]interface{}{
+					keys.Index: map[string]interface{}{"type": "integer"},
+					keys.Key:   map[string]interface{}{"type": "string"},
+				},
+			},
+		}
+	}
+	schema := map[string]interface{}{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"title":      "error",
+		"type":       "object",
+		"properties": properties,
+	}
+	if conf.schema >= 2 {
+		schema["required"] = []string{keys.Schema}
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
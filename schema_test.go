@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+)
+
+// logged returns the object logged for err with the schema version.
+func logged(t *testing.T, version int, err error) map[string]interface{} {
+	t.Helper()
+	defer CurrentConfig().Apply()
+	Configure(Schema(version))
+	core, logs := observer.New(zap.DebugLevel)
+	Log(zap.New(core), err)
+	return logs.AllUntimed()[0].ContextMap()["error"].(map[string]interface{})
+}
+
+func TestClassSchema(t *testing.T) {
+	if class, ok := logged(t, 2, Errorf("failure"))["class"]; ok {
+		t.Errorf("schema 2 logs class %v", class)
+	}
+	if class := logged(t, 3, Errorf("failure"))["class"]; class != ClassUnknown {
+		t.Errorf("schema 3 logs class %v, want %v", class, ClassUnknown)
+	}
+}
+
+// schema2 is the JSON Schema of version 2 of the layout, frozen: fields added
+// to the layout belong to a later version.
+const schema2 = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "properties": {
+    "code": {
+      "type": "integer"
+    },
+    "kind": {
+      "type": "string"
+    },
+    "message": {
+      "type": "string"
+    },
+    "payload": {},
+    "payload_size": {
+      "type": "integer"
+    },
+    "schema": {
+      "const": 2
+    },
+    "stacktrace": {
+      "type": "string"
+    }
+  },
+  "required": [
+    "schema"
+  ],
+  "title": "error",
+  "type": "object"
+}`
+
+func TestJSONSchema2(t *testing.T) {
+	defer CurrentConfig().Apply()
+	Configure(Schema(2))
+	schema, err := JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(schema) != schema2 {
+		t.Errorf("the schema 2 layout changed:\n%s", schema)
+	}
+}
+
+func TestSchema2Layout(t *testing.T) {
+	err := Errorf("failure: %w", fmt.Errorf("refused")).
+		WithCode(404).
+		WithKind(KindNotFound).
+		WithTenant("acme").
+		WithUser("jane").
+		WithGroupID("batch").
+		WithRetryable(true).
+		WithPayload("details")
+	object := logged(t, 2, err)
+	for key := range object {
+		switch key {
+		case "schema", "message", "code", "kind", "stacktrace", "payload", "payload_size":
+		default:
+			t.Errorf("schema 2 logs %s, added after the layout was frozen", key)
+		}
+	}
+	if len(logged(t, 3, err)) <= len(object) {
+		t.Error("schema 3 logs no more fields than schema 2")
+	}
+}
@@ -0,0 +1,40 @@
+package errors
+
+import "fmt"
+
+const secretMask = "***"
+
+// SecretValue holds a value that travels with an error but is never
+// rendered: it prints and marshals as "***". Use Value to read it back.
+type SecretValue struct {
+	value interface{}
+}
+
+// Secret wraps v so it can be put into payloads without being logged.
+func Secret(v interface{}) SecretValue {
+	return SecretValue{value: v}
+}
+
+func (sv SecretValue) Value() interface{} {
+	return sv.value
+}
+
+func (sv SecretValue) String() string {
+	return secretMask
+}
+
+func (sv SecretValue) GoString() string {
+	return secretMask
+}
+
+func (sv SecretValue) Format(state fmt.State, verb rune) {
+	_, _ = state.Write([]byte(secretMask))
+}
+
+func (sv SecretValue) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + secretMask + `"`), nil
+}
+
+func (sv SecretValue) MarshalText() ([]byte, error) {
+	return []byte(secretMask), nil
+}
@@ -0,0 +1,70 @@
+package errors
+
+import "go.uber.org/zap/zapcore"
+
+// Newf is like Errorf with an explicit severity. The stacktrace is only
+// captured when the severity reaches the StackLevel threshold.
+func Newf(level zapcore.Level, format string, a ...interface{}) Error {
+	ee := errorf(level, 1, format, a...)
+	ee.severity = level
+	ee.hasSeverity = true
+	return ee
+}
+
+// WithSeverity sets the level the error is logged at. A stacktrace already
+// captured is dropped when the severity is below the StackLevel threshold.
+func (ee Error) WithSeverity(level zapcore.Level) Error {
+	ee.severity = level
+	ee.hasSeverity = true
+	if !stackEnabled(level) {
+		ee.stacktrace = nil
+	}
+	return ee.enriched()
+}
+
+// StackLevel sets the minimum severity an error needs to get a stacktrace
+// captured by constructors and enrichment. Errors without an explicit severity
+// are considered errors. By default stacktraces are captured for every level.
+// The threshold can be changed at runtime with DiagnosticsHandler.
+func StackLevel(level zapcore.Level) Option {
+	return func(c *config) {
+		c.stackLevel = level
+	}
+}
+
+func stackEnabled(level zapcore.Level) bool {
+	if current, ok := diagnostics.Load().(*Diagnostics); ok && current != nil {
+		return level >= current.StackLevel
+	}
+	return level >= current().stackLevel
+}
+
+func (ee Error) level() zapcore.Level {
+	return ee.levelIn(ee)
+}
+
+// levelIn returns the level of ee, the first Error in the chain of err, the
+// code being resolved along err by the Resolve policy.
+func (ee Error) levelIn(err error) zapcore.Level {
+	if ee.hasSeverity {
+		return ee.severity
+	}
+	conf := current()
+	level, _ := conf.levels.level(resolveCode(err, conf), ee.kind)
+	return level
+}
+
+// LevelOf returns the level Log writes err at: the severity of the first
+// Error in its chain, the level the Levels policy gives its code and kind, or
+// error level.
+func LevelOf(err error) zapcore.Level {
+	return levelOf(err)
+}
+
+func levelOf(err error) zapcore.Level {
+	var ee Error
+	if asError(err, &ee) {
+		return ee.levelIn(err)
+	}
+	return zapcore.ErrorLevel
+}
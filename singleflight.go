@@ -0,0 +1,44 @@
+package errors
+
+type flightRole int8
+
+const (
+	flightNone flightRole = iota
+	flightLeader
+	flightFollower
+)
+
+// Flight annotates errors of calls deduplicated with
+// golang.org/x/sync/singleflight. Every caller uses its own Flight:
+//
+//	var flight errors.Flight
+//	v, err, shared := group.Do(key, flight.Fn(load))
+//	err = flight.Err(err, shared)
+//
+// Shared errors are logged with shared: true and the fingerprint of the
+// failure as leader. The caller that ran the function keeps the stacktrace,
+// the others only log the reference.
+type Flight struct {
+	leader bool
+}
+
+// Fn wraps fn to remember whether this caller ran it.
+func (f *Flight) Fn(fn func() (interface{}, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		f.leader = true
+		return fn()
+	}
+}
+
+// Err annotates the error returned by Do.
+func (f *Flight) Err(err error, shared bool) error {
+	if err == nil || !shared {
+		return err
+	}
+	ee := promote(err)
+	ee.flight = flightFollower
+	if f.leader {
+		ee.flight = flightLeader
+	}
+	return ee
+}
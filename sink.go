@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+// ErrSink logs errors produced by a pipeline stage far away from any logger.
+// Every error is logged with the stage name and the time it was received.
+type ErrSink struct {
+	logger  *zap.Logger
+	deduper *Deduper
+}
+
+func NewErrSink(logger *zap.Logger, stage string) *ErrSink {
+	return &ErrSink{logger: logger.With(zap.String("stage", stage))}
+}
+
+// Aggregate makes the sink collapse identical errors received within window
+// into one entry, see Deduper.
+func (s *ErrSink) Aggregate(window time.Duration, size int) *ErrSink {
+	s.deduper = NewDeduper(s.logger, window, size)
+	return s
+}
+
+// Run consumes errs until it is closed or ctx is done. Aggregated errors still
+// pending are written before Run returns.
+func (s *ErrSink) Run(ctx context.Context, errs <-chan error) {
+	if s.deduper != nil {
+		defer s.deduper.Flush()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err, ok := <-errs:
+			if !ok {
+				return
+			}
+			s.handle(err)
+		}
+	}
+}
+
+func (s *ErrSink) handle(err error) {
+	if err == nil {
+		return
+	}
+	received := zap.Time("received_at", time.Now())
+	if s.deduper != nil {
+		s.deduper.log(err, received)
+		return
+	}
+	logFields(err, []zapcore.Field{received}, s.logger)
+}
@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+	"time"
+)
+
+func TestErrSinkReceivedAt(t *testing.T) {
+	for name, aggregate := range map[string]bool{"plain": false, "aggregated": true} {
+		core, logs := observer.New(zap.DebugLevel)
+		sink := NewErrSink(zap.New(core), "ingest")
+		if aggregate {
+			sink.Aggregate(time.Hour, 8)
+		}
+		errs := make(chan error, 1)
+		errs <- Errorf("malformed record")
+		close(errs)
+		sink.Run(context.Background(), errs)
+
+		entries := logs.AllUntimed()
+		if len(entries) != 1 {
+			t.Fatalf("%s: got %d entries, want 1", name, len(entries))
+		}
+		fields := entries[0].ContextMap()
+		if _, ok := fields["received_at"]; !ok {
+			t.Errorf("%s: no received_at in %v", name, fields)
+		}
+		if fields["stage"] != "ingest" {
+			t.Errorf("%s: got stage %v", name, fields["stage"])
+		}
+	}
+}
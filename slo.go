@@ -0,0 +1,28 @@
+package errors
+
+// WithSLOImpact records whether the error impacts users, and thus the
+// service level objectives, as the slo_impact field. Stats sinks implementing
+// SLOStatsSink count impacting errors apart, so burn-rate alerts can be based
+// on them rather than on every logged failure.
+func (ee Error) WithSLOImpact(critical bool) Error {
+	return ee.WithField("slo_impact", critical)
+}
+
+// ImpactsSLO reports whether err was marked with WithSLOImpact(true).
+func ImpactsSLO(err error) bool {
+	var ee Error
+	return asError(err, &ee) && impactsSLO(ee)
+}
+
+func impactsSLO(ee Error) bool {
+	critical, _ := ee.fields["slo_impact"].(bool)
+	return critical
+}
+
+// SLOStatsSink is implemented by stats sinks counting the logged errors that
+// impact SLOs, see WithSLOImpact. LoggedSLOImpact is called in addition to
+// Logged.
+type SLOStatsSink interface {
+	StatsSink
+	LoggedSLOImpact(code int, kind Kind)
+}
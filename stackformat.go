@@ -0,0 +1,44 @@
+package errors
+
+import (
+	"io"
+	"runtime"
+	"strconv"
+)
+
+// StackFormat selects how logged stacktraces are rendered.
+type StackFormat int
+
+const (
+	// StackFormatDefault renders "function\t\nfile:line\n" per frame, followed
+	// by the arguments recorded with WithArg.
+	StackFormatDefault StackFormat = iota
+	// StackFormatZap renders stacktraces exactly like zap's own stacktrace
+	// field, "function\n\tfile:line" per frame separated by newlines, so
+	// log pipelines parse both with the same rules. Arguments recorded with
+	// WithArg are left out.
+	StackFormatZap
+)
+
+// Stacks sets the format of logged stacktraces.
+func Stacks(format StackFormat) Option {
+	return func(c *config) {
+		c.stackFormat = format
+	}
+}
+
+func writeZapStack(w io.Writer, frames []*runtime.Frame) {
+	var line []byte
+	for i, frame := range frames {
+		line = line[:0]
+		if i != 0 {
+			line = append(line, '\n')
+		}
+		line = append(line, frame.Function...)
+		line = append(line, '\n', '\t')
+		line = append(line, frame.File...)
+		line = append(line, ':')
+		line = strconv.AppendInt(line, int64(frame.Line), 10)
+		_, _ = w.Write(line)
+	}
+}
@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"expvar"
+	"strconv"
+	"time"
+)
+
+// StatsSink receives a notification for every Error created by the package
+// constructors and every error written by Log. Code and kind are only known
+// once an error is logged, constructors report the bare creation. Warnings
+// are not reported as logged unless the WarningPolicy counts them.
+type StatsSink interface {
+	Created()
+	Logged(code int, kind Kind)
+}
+
+// Stats sets the sink notified about created and logged errors.
+func Stats(sink StatsSink) Option {
+	return func(c *config) {
+		c.stats = sink
+	}
+}
+
+func statsCreated() {
+	if stats := current().stats; stats != nil {
+		stats.Created()
+	}
+}
+
+func statsLogged(err error) {
+	conf := current()
+	if conf.stats == nil && conf.latency == nil {
+		return
+	}
+	var ee Error
+	asError(err, &ee)
+	if conf.stats != nil && (!ee.warning || conf.warnings.Counted) {
+		code := resolveCode(err, conf)
+		conf.stats.Logged(code, ee.kind)
+		if sink, ok := conf.stats.(SLOStatsSink); ok && impactsSLO(ee) {
+			sink.LoggedSLOImpact(code, ee.kind)
+		}
+	}
+	if conf.latency != nil && ee.mark != nil && !ee.mark.created.IsZero() {
+		conf.latency.Observe(time.Since(ee.mark.created))
+	}
+}
+
+// ExpvarStats is a StatsSink publishing its counters through expvar, so they
+// are served at /debug/vars.
+type ExpvarStats struct {
+	created      *expvar.Int
+	logged       *expvar.Int
+	loggedByCode *expvar.Map
+	loggedByKind *expvar.Map
+	sloImpact    *expvar.Int
+}
+
+// NewExpvarStats publishes the counters under name: created, logged,
+// logged_by_code, logged_by_kind and logged_slo_impact, the number of logged
+// errors marked with WithSLOImpact(true). Like expvar.Publish it panics when
+// name is already in use.
+func NewExpvarStats(name string) *ExpvarStats {
+	stats := &ExpvarStats{
+		created:      new(expvar.Int),
+		logged:       new(expvar.Int),
+		loggedByCode: new(expvar.Map).Init(),
+		loggedByKind: new(expvar.Map).Init(),
+		sloImpact:    new(expvar.Int),
+	}
+	root := expvar.NewMap(name)
+	root.Set("created", stats.created)
+	root.Set("logged", stats.logged)
+	root.Set("logged_by_code", stats.loggedByCode)
+	root.Set("logged_by_kind", stats.loggedByKind)
+	root.Set("logged_slo_impact", stats.sloImpact)
+	return stats
+}
+
+func (s *ExpvarStats) Created() {
+	s.created.Add(1)
+}
+
+func (s *ExpvarStats) Logged(code int, kind Kind) {
+	s.logged.Add(1)
+	s.loggedByCode.Add(strconv.Itoa(code), 1)
+	if kind == KindUnknown {
+		kind = "unknown"
+	}
+	s.loggedByKind.Add(string(kind), 1)
+}
+
+// LoggedSLOImpact counts a logged error impacting SLOs in
+// logged_slo_impact, on top of the count of Logged.
+func (s *ExpvarStats) LoggedSLOImpact(code int, kind Kind) {
+	s.sloImpact.Add(1)
+}
@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+type countingStats struct {
+	created, logged int64
+}
+
+func (s *countingStats) Created() {
+	atomic.AddInt64(&s.created, 1)
+}
+
+func (s *countingStats) Logged(int, Kind) {
+	atomic.AddInt64(&s.logged, 1)
+}
+
+func TestStatsCountWrapsOfErrorsOnce(t *testing.T) {
+	defer CurrentConfig().Apply()
+	stats := &countingStats{}
+	Configure(Stats(stats))
+
+	err := Errorf("failure")
+	err = WithMessage(err, "reading")
+	_ = Wrap(fmt.Errorf("decoding: %w", err), "loading")
+	if stats.created != 1 {
+		t.Errorf("got %d errors created, want 1", stats.created)
+	}
+	_ = Wrap(fmt.Errorf("refused"), "dialing")
+	if stats.created != 2 {
+		t.Errorf("got %d errors created, wrapping a foreign error creates one", stats.created)
+	}
+}
@@ -0,0 +1,32 @@
+package errors
+
+import "net/http"
+
+var kindStatuses = map[Kind]int{
+	KindInvalid:          http.StatusBadRequest,
+	KindUnauthenticated:  http.StatusUnauthorized,
+	KindPermissionDenied: http.StatusForbidden,
+	KindNotFound:         http.StatusNotFound,
+	KindConflict:         http.StatusConflict,
+	KindCanceled:         499,
+	KindTimeout:          http.StatusGatewayTimeout,
+	KindUnavailable:      http.StatusServiceUnavailable,
+	KindTLS:              http.StatusBadGateway,
+	KindInternal:         http.StatusInternalServerError,
+}
+
+// StatusOf maps err to an HTTP status: codes in the 400-599 range are taken
+// as statuses, otherwise the kind decides, defaulting to 500.
+func StatusOf(err error) int {
+	var ee Error
+	if !asError(err, &ee) {
+		return http.StatusInternalServerError
+	}
+	if code := resolveCode(err, current()); code >= 400 && code < 600 {
+		return code
+	}
+	if status, ok := kindStatuses[ee.kind]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"bytes"
+	"go.uber.org/zap"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// Strict makes misuses of the package, such as wrapping a nil error, logging
+// to a nil logger or calling Log while an error is being logged, DPanic
+// through the logger involved, or the global logger when there is none.
+// Development loggers then panic, surfacing integration bugs in tests. Out of
+// strict mode, misuses are tolerated: nil loggers are skipped.
+func Strict(enabled bool) Option {
+	return func(c *config) {
+		c.strict = enabled
+	}
+}
+
+func misuse(logger *zap.Logger, message string) {
+	if !current().strict {
+		return
+	}
+	if logger == nil {
+		logger = zap.L()
+	}
+	logger.DPanic(message)
+}
+
+// logging holds the ids of the goroutines running Log, to detect re-entrant
+// calls in strict mode.
+var logging sync.Map
+
+// enterLog records the current goroutine as logging, reporting a misuse if it
+// already is, and returns the function to call when done.
+func enterLog(loggers []*zap.Logger) func() {
+	id := goroutineID()
+	if _, reentrant := logging.LoadOrStore(id, true); reentrant {
+		var logger *zap.Logger
+		if len(loggers) > 0 {
+			logger = loggers[0]
+		}
+		misuse(logger, "Log called while logging an error")
+		return func() {}
+	}
+	return func() {
+		logging.Delete(id)
+	}
+}
+
+// goroutineID parses the id of the current goroutine from its stack header,
+// "goroutine 42 [running]:". It is slow and only meant for strict mode.
+func goroutineID() uint64 {
+	var buffer [64]byte
+	header := buffer[:runtime.Stack(buffer[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	if i := bytes.IndexByte(header, ' '); i >= 0 {
+		header = header[:i]
+	}
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+	return id
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"sort"
+	"strings"
+)
+
+// SyslogSeverity returns the syslog severity, from 0 (emergency) to 7
+// (debug), matching the level err is logged at. The level follows the
+// severity of err, or the Levels policy for its code and kind.
+func SyslogSeverity(err error) int {
+	switch levelOf(err) {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel:
+		return 2
+	case zapcore.PanicLevel:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// StructuredData renders err as an RFC 5424 structured data element with the
+// given SD-ID, such as "error@32473". Parameters are the fields of the logged
+// error object with nested keys joined by dots, the stacktrace left out.
+// Names are made valid by replacing forbidden characters with underscores.
+func StructuredData(err error, id string) string {
+	if err == nil {
+		return "-"
+	}
+	object := ZerologDict(err)
+	delete(object, current().keys.Stacktrace)
+	params := make(map[string]interface{})
+	flatten("", object, params)
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString("[")
+	b.WriteString(sdName(id))
+	for _, name := range names {
+		fmt.Fprintf(&b, " %s=\"%s\"", sdName(name), sdEscaper.Replace(fmt.Sprint(params[name])))
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+var sdEscaper = strings.NewReplacer(`"`, `\"`, `\`, `\\`, `]`, `\]`)
+
+// sdName makes name a valid SD-NAME: at most 32 printable ASCII characters
+// other than '=', ' ', ']' and '"'.
+func sdName(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		if c <= ' ' || c > '~' || c == '=' || c == ']' || c == '"' {
+			b[i] = '_'
+		}
+	}
+	if len(b) > 32 {
+		b = b[:32]
+	}
+	return string(b)
+}
@@ -0,0 +1,106 @@
+package errors
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Redaction lists the parts of errors removed by loggers built with Redact.
+type Redaction struct {
+	// Message replaces messages, including the log entry message, by the
+	// error kind.
+	Message    bool
+	Stacktrace bool
+	Payload    bool
+	// Fields covers structured fields, debug arguments, message chains and
+	// batch children.
+	Fields bool
+}
+
+// SummaryRedaction keeps messages, codes and kinds only.
+var SummaryRedaction = Redaction{Stacktrace: true, Payload: true, Fields: true}
+
+// LogTee logs err to every logger, counting it once for stats, audit and the
+// Relog policy. Combined with Redact, each logger gets its own level of
+// detail, such as full errors in the internal log and summaries in the
+// customer-visible one.
+func LogTee(err error, loggers ...*zap.Logger) {
+	logTo(err, loggers...)
+}
+
+// Redact returns a logger removing the parts listed in redaction from the
+// errors it writes.
+func Redact(logger *zap.Logger, redaction Redaction) *zap.Logger {
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return redactingCore{Core: core, redaction: redaction}
+	}))
+}
+
+type redactingCore struct {
+	zapcore.Core
+	redaction Redaction
+}
+
+func (c redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return redactingCore{Core: c.Core.With(c.redactFields(fields)), redaction: c.redaction}
+}
+
+func (c redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	redacted := c.redactFields(fields)
+	if c.redaction.Message {
+		for _, field := range fields {
+			if ee, ok := fieldError(field); ok {
+				entry.Message = redactedMessage(ee)
+				break
+			}
+		}
+	}
+	return writeChecked(c.Core, entry, redacted)
+}
+
+func (c redactingCore) redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		switch e := field.Interface.(type) {
+		case Error:
+			if field.Type == zapcore.ObjectMarshalerType {
+				field.Interface = c.redaction.apply(e)
+			}
+		case configuredError:
+			field.Interface = configuredError{err: c.redaction.apply(e.err), conf: e.conf}
+		}
+		redacted[i] = field
+	}
+	return redacted
+}
+
+func (r Redaction) apply(ee Error) Error {
+	if r.Message {
+		ee.base = ee.base.WithText(redactedMessage(ee)).WithCause(nil)
+	}
+	if r.Stacktrace {
+		ee.stacktrace = nil
+	}
+	if r.Payload {
+		ee.base = ee.base.WithPayload(nil)
+		ee.payloadRedacted = true
+	}
+	if r.Fields {
+		ee.fields, ee.args, ee.chain, ee.children = nil, nil, nil, nil
+	}
+	return ee
+}
+
+func redactedMessage(ee Error) string {
+	if ee.kind != KindUnknown {
+		return string(ee.kind)
+	}
+	return "error"
+}
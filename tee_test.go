@@ -0,0 +1,32 @@
+package errors
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+)
+
+func TestRedactPayloadOfInnerLayers(t *testing.T) {
+	for _, policy := range []Resolution{ResolveOutermost, ResolveInnermost, ResolveAll} {
+		func() {
+			defer CurrentConfig().Apply()
+			Configure(Resolve(policy))
+			core, logs := observer.New(zap.DebugLevel)
+			inner := Errorf("inner").WithPayload(map[string]string{"token": "SECRET"})
+			// Errorf wraps with %w like fmt.Errorf, vet only knows about the latter.
+			wrapping := "outer: %w"
+			outer := Errorf(wrapping, inner)
+
+			Log(Redact(zap.New(core), SummaryRedaction), outer)
+
+			entries := logs.AllUntimed()
+			if len(entries) != 1 {
+				t.Fatalf("policy %d: got %d entries, want 1", policy, len(entries))
+			}
+			object := entries[0].ContextMap()["error"].(map[string]interface{})
+			if payload, ok := object["payload"]; ok {
+				t.Errorf("policy %d: payload %v logged by a redacting logger", policy, payload)
+			}
+		}()
+	}
+}
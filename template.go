@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"fmt"
+	"github.com/jpascal/zap-errors/core"
+	"go.uber.org/zap/zapcore"
+	"strings"
+)
+
+// Args are the named arguments of a message template.
+type Args map[string]interface{}
+
+// NewT creates an Error whose message is rendered from template by replacing
+// {name} placeholders with the matching args. The args are kept as structured
+// fields, so the values are not duplicated between message and payload.
+// Placeholders without an argument are left untouched.
+func NewT(template string, args Args) Error {
+	statsCreated()
+	ee := Error{
+		base:       core.New(renderTemplate(template, args), nil),
+		stacktrace: stackTraceAt(zapcore.ErrorLevel, 0),
+		mark:       newMark(),
+	}
+	if len(args) > 0 {
+		ee.fields = make(map[string]interface{}, len(args))
+		for key, value := range args {
+			ee.fields[key] = value
+		}
+	}
+	return ee
+}
+
+func renderTemplate(template string, args Args) string {
+	builder := &strings.Builder{}
+	for {
+		open := strings.IndexByte(template, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(template[open:], '}')
+		if end < 0 {
+			break
+		}
+		end += open
+		builder.WriteString(template[:open])
+		if value, ok := args[template[open+1:end]]; ok {
+			_, _ = fmt.Fprint(builder, value)
+		} else {
+			builder.WriteString(template[open : end+1])
+		}
+		template = template[end+1:]
+	}
+	builder.WriteString(template)
+	return builder.String()
+}
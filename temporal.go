@@ -0,0 +1,105 @@
+package errors
+
+import (
+	"github.com/jpascal/zap-errors/core"
+	"go.uber.org/zap/zapcore"
+)
+
+// ApplicationError holds what Temporal needs to build an application error,
+// so workflow code keeps codes and payloads across the activity boundary
+// without this package depending on the SDK:
+//
+//	a := errors.ToApplicationError(err)
+//	return temporal.NewApplicationErrorWithOptions(a.Message, a.Type, temporal.ApplicationErrorOptions{
+//		NonRetryable: a.NonRetryable,
+//		Details:      []interface{}{a.Details},
+//		Cause:        a.Cause,
+//	})
+type ApplicationError struct {
+	Message      string
+	Type         string
+	NonRetryable bool
+	Details      ApplicationDetails
+	Cause        error
+}
+
+// ApplicationDetails is the details payload of converted application errors.
+type ApplicationDetails struct {
+	Code    int                    `json:"code,omitempty"`
+	Kind    Kind                   `json:"kind,omitempty"`
+	Payload interface{}            `json:"payload,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// ToApplicationError converts err. The kind becomes the error type, errors
+// without a kind get the type of their underlying cause. Errors are
+// non-retryable when marked permanent.
+func ToApplicationError(err error) ApplicationError {
+	if err == nil {
+		return ApplicationError{}
+	}
+	var ee Error
+	if !asError(err, &ee) {
+		return ApplicationError{Message: err.Error(), Type: causeType(err), Cause: err}
+	}
+	conf := current()
+	errType := string(ee.kind)
+	if errType == "" {
+		errType = causeType(ee.base.Unwrap())
+	}
+	return ApplicationError{
+		Message:      ee.base.Message(),
+		Type:         errType,
+		NonRetryable: IsPermanent(err),
+		Details: ApplicationDetails{
+			Code:    resolveCode(err, conf),
+			Kind:    ee.kind,
+			Payload: resolvePayload(err, conf),
+			Fields:  ee.fields,
+		},
+		Cause: ee.base.Unwrap(),
+	}
+}
+
+// temporalApplicationError is implemented by *temporal.ApplicationError.
+type temporalApplicationError interface {
+	error
+	Type() string
+	NonRetryable() bool
+	HasDetails() bool
+	Details(d ...interface{}) error
+}
+
+// FromApplicationError restores an Error from a Temporal application error
+// found in the chain of err, decoding the details written by
+// ToApplicationError. Other errors are wrapped as is.
+func FromApplicationError(err error) Error {
+	var appError temporalApplicationError
+	found := false
+	Walk(err, func(err error) bool {
+		appError, found = err.(temporalApplicationError)
+		return !found
+	})
+	ee := Error{
+		base:       core.New(err.Error(), err),
+		stacktrace: stackTraceAt(zapcore.ErrorLevel, 0),
+		mark:       newMark(),
+	}
+	if !found {
+		return ee
+	}
+	var details ApplicationDetails
+	if appError.HasDetails() && appError.Details(&details) == nil {
+		ee.base = ee.base.WithCode(details.Code)
+		ee.kind = details.Kind
+		ee.base = ee.base.WithPayload(details.Payload)
+		ee.fields = details.Fields
+	}
+	if ee.kind == KindUnknown {
+		ee.kind = Kind(appError.Type())
+	}
+	if appError.NonRetryable() {
+		ee.retry = retryNo
+	}
+	return ee
+}
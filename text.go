@@ -0,0 +1,206 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// TextOptions controls FormatText. The zero value renders everything.
+type TextOptions struct {
+	NoChain   bool
+	NoStack   bool
+	NoPayload bool
+	// PathSegments, when positive, keeps only the last segments of frame
+	// file paths.
+	PathSegments int
+	// ShortenPackages shortens module paths in frame functions to their last
+	// element, github.com/org/repo/pkg.Func becoming …/repo/pkg.Func.
+	ShortenPackages bool
+	// MaxWidth, when positive, truncates stack lines to that many runes.
+	MaxWidth int
+}
+
+// FormatText renders err as human readable multi-line text, for CLIs, panic
+// messages and test failures. It does not involve a zap encoder.
+func FormatText(err error, opts TextOptions) string {
+	if err == nil {
+		return ""
+	}
+	builder := &strings.Builder{}
+	builder.WriteString(err.Error())
+	builder.WriteByte('\n')
+	var ee Error
+	isError := asError(err, &ee)
+	conf := current()
+	if code := resolveCode(err, conf); code != 0 {
+		_, _ = fmt.Fprintf(builder, "  code: %d\n", code)
+	}
+	if isError && ee.kind != KindUnknown {
+		_, _ = fmt.Fprintf(builder, "  kind: %s\n", ee.kind)
+	}
+	if isError && ee.position != nil {
+		writeExcerpt(builder, ee.position, "  ")
+	}
+	if !opts.NoChain {
+		if cause := errors.Unwrap(err); cause != nil {
+			builder.WriteString("  caused by:\n")
+			for ; cause != nil; cause = errors.Unwrap(cause) {
+				_, _ = fmt.Fprintf(builder, "    - %s\n", cause.Error())
+			}
+		}
+	}
+	if isError && len(ee.children) > 0 {
+		builder.WriteString("  errors:\n")
+		for _, c := range ee.children {
+			if c.key != "" {
+				_, _ = fmt.Fprintf(builder, "    - %s: %s\n", c.key, c.err.Error())
+			} else {
+				_, _ = fmt.Fprintf(builder, "    - [%d] %s\n", c.index, c.err.Error())
+			}
+			if child, ok := errorOf(c.err); ok && child.position != nil {
+				writeExcerpt(builder, child.position, "      ")
+			}
+		}
+	}
+	if !opts.NoStack && isError && len(ee.stacktrace) > 0 {
+		switch {
+		case ee.panicked && ee.goroutine != "":
+			_, _ = fmt.Fprintf(builder, "  panic stack (%s):\n", ee.goroutine)
+		case ee.panicked:
+			builder.WriteString("  panic stack:\n")
+		default:
+			builder.WriteString("  stack:\n")
+		}
+		writeTextFrames(builder, ee.stacktrace, opts)
+		if len(ee.recoveredAt) > 0 {
+			builder.WriteString("  recovered at:\n")
+			writeTextFrames(builder, ee.recoveredAt, opts)
+		}
+	}
+	if payload := resolvePayload(err, conf); !opts.NoPayload && payload != nil {
+		builder.WriteString("  payload:")
+		writeYAMLish(builder, reflect.ValueOf(payload), "    ")
+	}
+	return builder.String()
+}
+
+func writeTextFrames(builder *strings.Builder, frames []*runtime.Frame, opts TextOptions) {
+	for _, frame := range frames {
+		function, file := frame.Function, frame.File
+		if opts.ShortenPackages {
+			function = shortenPackage(function)
+		}
+		if opts.PathSegments > 0 {
+			file = lastSegments(file, opts.PathSegments)
+		}
+		builder.WriteString(truncate("    "+function, opts.MaxWidth))
+		builder.WriteByte('\n')
+		builder.WriteString(truncate(fmt.Sprintf("        %s:%d", file, frame.Line), opts.MaxWidth))
+		builder.WriteByte('\n')
+	}
+}
+
+func writeYAMLish(builder *strings.Builder, value reflect.Value, indent string) {
+	for value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			builder.WriteString(" null\n")
+			return
+		}
+		if isScalarLike(value) {
+			break
+		}
+		value = value.Elem()
+	}
+	if isScalarLike(value) {
+		_, _ = fmt.Fprintf(builder, " %v\n", value.Interface())
+		return
+	}
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Len() == 0 {
+			builder.WriteString(" {}\n")
+			return
+		}
+		builder.WriteByte('\n')
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			_, _ = fmt.Fprintf(builder, "%s%v:", indent, key.Interface())
+			writeYAMLish(builder, value.MapIndex(key), indent+"  ")
+		}
+	case reflect.Struct:
+		builder.WriteByte('\n')
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			_, _ = fmt.Fprintf(builder, "%s%s:", indent, field.Name)
+			writeYAMLish(builder, value.Field(i), indent+"  ")
+		}
+	case reflect.Slice, reflect.Array:
+		if value.Len() == 0 {
+			builder.WriteString(" []\n")
+			return
+		}
+		builder.WriteByte('\n')
+		for i := 0; i < value.Len(); i++ {
+			_, _ = fmt.Fprintf(builder, "%s-", indent)
+			writeYAMLish(builder, value.Index(i), indent+"  ")
+		}
+	default:
+		_, _ = fmt.Fprintf(builder, " %v\n", value.Interface())
+	}
+}
+
+func isScalarLike(value reflect.Value) bool {
+	if !value.IsValid() || !value.CanInterface() {
+		return true
+	}
+	switch value.Interface().(type) {
+	case fmt.Stringer, error, []byte:
+		return true
+	}
+	switch value.Kind() {
+	case reflect.Map, reflect.Struct, reflect.Slice, reflect.Array, reflect.Interface, reflect.Ptr:
+		return false
+	}
+	return true
+}
+
+func shortenPackage(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if slash < 0 {
+		return function
+	}
+	parent := strings.LastIndex(function[:slash], "/")
+	if parent < 0 {
+		return function
+	}
+	return "…" + function[parent:]
+}
+
+func lastSegments(path string, segments int) string {
+	parts := strings.Split(path, "/")
+	if len(parts) <= segments {
+		return path
+	}
+	return "…/" + strings.Join(parts[len(parts)-segments:], "/")
+}
+
+func truncate(line string, width int) string {
+	if width <= 0 {
+		return line
+	}
+	runes := []rune(line)
+	if len(runes) <= width {
+		return line
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"fmt"
+	"time"
+)
+
+// WrapTimed is Wrap for an operation started at start: the time it took
+// until failing is recorded as the elapsed field.
+func WrapTimed(start time.Time, err error, format string, a ...interface{}) error {
+	if err == nil && !current().wrapNil {
+		return nil
+	}
+	return wrap(err, fmt.Sprintf(format, a...)).WithField("elapsed", time.Since(start))
+}
+
+// Timer measures an operation for the errors it returns:
+//
+//	timer := errors.StartTimer()
+//	if err := query(ctx); err != nil {
+//		return timer.Wrap(err, "querying users")
+//	}
+type Timer struct {
+	start time.Time
+}
+
+// StartTimer starts a Timer at the current time.
+func StartTimer() Timer {
+	return Timer{start: time.Now()}
+}
+
+// Elapsed returns the time since the Timer was started.
+func (t Timer) Elapsed() time.Duration {
+	return time.Since(t.start)
+}
+
+// Wrap is WrapTimed from the start of the Timer.
+func (t Timer) Wrap(err error, format string, a ...interface{}) error {
+	if err == nil && !current().wrapNil {
+		return nil
+	}
+	return wrap(err, fmt.Sprintf(format, a...)).WithField("elapsed", time.Since(t.start))
+}
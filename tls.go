@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+)
+
+func init() {
+	causeEnrichers = append(causeEnrichers, enrichTLS)
+}
+
+// enrichTLS recognizes certificate verification and handshake failures,
+// alerts sent by either side included, records the certificate details and
+// the alert as tls.* fields and sets KindTLS, taking precedence over the
+// kinds of the network errors they usually hide behind.
+func enrichTLS(ee Error, cause error) Error {
+	var invalidError x509.CertificateInvalidError
+	var hostnameError x509.HostnameError
+	var authorityError x509.UnknownAuthorityError
+	var recordError tls.RecordHeaderError
+	fields := map[string]interface{}{}
+	switch {
+	case errors.As(cause, &invalidError):
+		fields["tls.reason"] = invalidReason(invalidError.Reason)
+		addCertificate(fields, invalidError.Cert)
+	case errors.As(cause, &hostnameError):
+		fields["tls.reason"] = "hostname_mismatch"
+		fields["tls.host"] = hostnameError.Host
+		addCertificate(fields, hostnameError.Certificate)
+	case errors.As(cause, &authorityError):
+		fields["tls.reason"] = "unknown_authority"
+		addCertificate(fields, authorityError.Cert)
+	case errors.As(cause, &recordError):
+		fields["tls.reason"] = "handshake"
+	default:
+		alert := tlsAlert(cause)
+		if alert == "" {
+			return ee
+		}
+		fields["tls.reason"] = "handshake"
+		fields["tls.alert"] = alert
+	}
+	ee = ee.WithFields(fields)
+	ee.kind = KindTLS
+	return ee
+}
+
+// tlsAlertText returns the description of an alert sent by the peer, such as
+// "handshake failure", read from the remote error reporting it. Alerts are
+// of an unexported type, their text is all there is to go on.
+func tlsAlertText(err error) string {
+	var opError *net.OpError
+	if !errors.As(err, &opError) || opError.Op != "remote error" || opError.Err == nil {
+		return ""
+	}
+	description := opError.Err.Error()
+	if !strings.HasPrefix(description, "tls: ") {
+		return ""
+	}
+	return strings.TrimPrefix(description, "tls: ")
+}
+
+func addCertificate(fields map[string]interface{}, cert *x509.Certificate) {
+	if cert == nil {
+		return
+	}
+	fields["tls.subject"] = cert.Subject.String()
+	fields["tls.issuer"] = cert.Issuer.String()
+	fields["tls.not_before"] = cert.NotBefore
+	fields["tls.not_after"] = cert.NotAfter
+}
+
+func invalidReason(reason x509.InvalidReason) string {
+	switch reason {
+	case x509.Expired:
+		return "expired"
+	case x509.NotAuthorizedToSign:
+		return "not_authorized_to_sign"
+	case x509.CANotAuthorizedForThisName:
+		return "ca_not_authorized_for_name"
+	case x509.TooManyIntermediates:
+		return "too_many_intermediates"
+	case x509.IncompatibleUsage:
+		return "incompatible_usage"
+	case x509.NameMismatch:
+		return "name_mismatch"
+	}
+	return "invalid_certificate"
+}
@@ -0,0 +1,20 @@
+//go:build go1.21
+
+package errors
+
+import (
+	"crypto/tls"
+	"errors"
+	"strings"
+)
+
+// tlsAlert returns the description of the alert that failed a handshake, as
+// a tls.AlertError for alerts we sent or as the remote error for alerts sent
+// by the peer, or "".
+func tlsAlert(err error) string {
+	var alertError tls.AlertError
+	if errors.As(err, &alertError) {
+		return strings.TrimPrefix(alertError.Error(), "tls: ")
+	}
+	return tlsAlertText(err)
+}
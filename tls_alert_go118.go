@@ -0,0 +1,9 @@
+//go:build !go1.21
+
+package errors
+
+// tlsAlert returns the description of the alert that failed a handshake, as
+// reported by the remote error for alerts sent by the peer, or "".
+func tlsAlert(err error) string {
+	return tlsAlertText(err)
+}
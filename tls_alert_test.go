@@ -0,0 +1,23 @@
+//go:build go1.21
+
+package errors
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTLSAlertError(t *testing.T) {
+	// QUIC connections report the alert they sent like this, see
+	// tls.QUICConn.
+	handshakeErr := fmt.Errorf("%w%.0w", errors.New("tls: client offered only unsupported versions"), tls.AlertError(70))
+	ee := WithMessage(handshakeErr, "accepting")
+	if ee.kind != KindTLS {
+		t.Errorf("got kind %q for %v, want %q", ee.kind, handshakeErr, KindTLS)
+	}
+	if alert := ee.fields["tls.alert"]; alert != "protocol version not supported" {
+		t.Errorf("got alert %v", alert)
+	}
+}
@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"testing"
+)
+
+// failedHandshake runs a handshake failing on the protocol version over an
+// in-memory connection and returns the errors of both sides.
+func failedHandshake(t *testing.T) (clientErr, serverErr error) {
+	t.Helper()
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+	done := make(chan error, 1)
+	go func() {
+		done <- tls.Server(serverConn, &tls.Config{MinVersion: tls.VersionTLS13}).Handshake()
+	}()
+	clientErr = tls.Client(clientConn, &tls.Config{
+		MaxVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: true,
+	}).Handshake()
+	serverErr = <-done
+	if clientErr == nil || serverErr == nil {
+		t.Fatalf("handshake did not fail: client %v, server %v", clientErr, serverErr)
+	}
+	return clientErr, serverErr
+}
+
+func TestTLSRemoteAlert(t *testing.T) {
+	clientErr, _ := failedHandshake(t)
+	ee := WithMessage(clientErr, "dialing")
+	if ee.kind != KindTLS {
+		t.Errorf("got kind %q for %v, want %q", ee.kind, clientErr, KindTLS)
+	}
+	if alert := ee.fields["tls.alert"]; alert != "protocol version not supported" {
+		t.Errorf("got alert %v", alert)
+	}
+}
+
+func TestTLSAlertText(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want string
+	}{
+		{&net.OpError{Op: "remote error", Err: errors.New("tls: handshake failure")}, "handshake failure"},
+		{&net.OpError{Op: "read", Err: errors.New("tls: handshake failure")}, ""},
+		{&net.OpError{Op: "remote error", Err: errors.New("connection reset")}, ""},
+		{errors.New("tls: handshake failure"), ""},
+	} {
+		if got := tlsAlertText(tt.err); got != tt.want {
+			t.Errorf("%v: got alert %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestTLSRecordHeader(t *testing.T) {
+	ee := WithMessage(tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}, "dialing")
+	if ee.kind != KindTLS || ee.fields["tls.reason"] != "handshake" {
+		t.Errorf("got kind %q and fields %v", ee.kind, ee.fields)
+	}
+}
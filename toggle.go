@@ -0,0 +1,108 @@
+package errors
+
+import (
+	"encoding/json"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Diagnostics are the settings that can be changed while the program runs,
+// see DiagnosticsHandler.
+type Diagnostics struct {
+	// StackLevel is the minimum severity getting a stacktrace captured,
+	// overriding the StackLevel option.
+	StackLevel zapcore.Level `json:"stack_level"`
+	// Payloads controls whether payloads are logged.
+	Payloads bool `json:"payloads"`
+	// Dedup controls whether Dedupers collapse identical errors, when
+	// disabled every error is written as it is logged.
+	Dedup bool `json:"dedup"`
+}
+
+// diagnostics holds the *Diagnostics set at runtime, nil until the first
+// change so the configured settings apply.
+var (
+	diagnostics   atomic.Value
+	diagnosticsMu sync.Mutex
+)
+
+// CurrentDiagnostics returns the settings in effect.
+func CurrentDiagnostics() Diagnostics {
+	if current, ok := diagnostics.Load().(*Diagnostics); ok && current != nil {
+		return *current
+	}
+	return Diagnostics{StackLevel: current().stackLevel, Payloads: true, Dedup: true}
+}
+
+// SetDiagnostics overrides the configured settings until ResetDiagnostics.
+func SetDiagnostics(d Diagnostics) {
+	diagnostics.Store(&d)
+}
+
+// ResetDiagnostics restores the settings set with Configure.
+func ResetDiagnostics() {
+	diagnostics.Store((*Diagnostics)(nil))
+}
+
+func payloadsEnabled() bool {
+	current, ok := diagnostics.Load().(*Diagnostics)
+	return !ok || current == nil || current.Payloads
+}
+
+func dedupEnabled() bool {
+	current, ok := diagnostics.Load().(*Diagnostics)
+	return !ok || current == nil || current.Dedup
+}
+
+// DiagnosticsHandler returns an http.Handler exposing the Diagnostics, akin
+// to zap.AtomicLevel, so deep diagnostics can be turned on during an
+// incident without redeploying:
+//
+//	GET     returns the settings in effect as JSON.
+//	PUT     changes the settings present in the JSON body, e.g.
+//	        {"stack_level": "info", "payloads": false}.
+//	DELETE  restores the configured settings.
+func DiagnosticsHandler() http.Handler {
+	return http.HandlerFunc(serveDiagnostics)
+}
+
+func serveDiagnostics(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var update struct {
+			StackLevel *zapcore.Level `json:"stack_level"`
+			Payloads   *bool          `json:"payloads"`
+			Dedup      *bool          `json:"dedup"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		diagnosticsMu.Lock()
+		current := CurrentDiagnostics()
+		if update.StackLevel != nil {
+			current.StackLevel = *update.StackLevel
+		}
+		if update.Payloads != nil {
+			current.Payloads = *update.Payloads
+		}
+		if update.Dedup != nil {
+			current.Dedup = *update.Dedup
+		}
+		SetDiagnostics(current)
+		diagnosticsMu.Unlock()
+	case http.MethodDelete:
+		ResetDiagnostics()
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(CurrentDiagnostics())
+}
@@ -0,0 +1,149 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FormatTOML renders err like Field does, as a TOML document holding an
+// error table. Nested objects become sub-tables, the children of joined
+// errors an array of tables.
+func FormatTOML(err error) (string, error) {
+	if err == nil {
+		return "", nil
+	}
+	// Reflected payloads are normalized to plain maps, slices and scalars.
+	data, marshalErr := json.Marshal(ZerologDict(err))
+	if marshalErr != nil {
+		return "", marshalErr
+	}
+	var object map[string]interface{}
+	if unmarshalErr := json.Unmarshal(data, &object); unmarshalErr != nil {
+		return "", unmarshalErr
+	}
+	builder := &strings.Builder{}
+	builder.WriteString("[error]\n")
+	writeTOMLTable(builder, "error", object)
+	return builder.String(), nil
+}
+
+func writeTOMLTable(builder *strings.Builder, path string, table map[string]interface{}) {
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var tables, arrays []string
+	for _, key := range keys {
+		switch value := table[key].(type) {
+		case nil:
+		case map[string]interface{}:
+			tables = append(tables, key)
+		case []interface{}:
+			if isTOMLTableArray(value) {
+				arrays = append(arrays, key)
+				continue
+			}
+			_, _ = fmt.Fprintf(builder, "%s = %s\n", tomlKey(key), tomlValue(value))
+		default:
+			_, _ = fmt.Fprintf(builder, "%s = %s\n", tomlKey(key), tomlValue(value))
+		}
+	}
+	for _, key := range tables {
+		subpath := path + "." + tomlKey(key)
+		_, _ = fmt.Fprintf(builder, "\n[%s]\n", subpath)
+		writeTOMLTable(builder, subpath, table[key].(map[string]interface{}))
+	}
+	for _, key := range arrays {
+		subpath := path + "." + tomlKey(key)
+		for _, item := range table[key].([]interface{}) {
+			_, _ = fmt.Fprintf(builder, "\n[[%s]]\n", subpath)
+			writeTOMLTable(builder, subpath, item.(map[string]interface{}))
+		}
+	}
+}
+
+func isTOMLTableArray(values []interface{}) bool {
+	for _, value := range values {
+		if _, ok := value.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return len(values) > 0
+}
+
+func tomlValue(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return tomlString(value)
+	case bool:
+		return strconv.FormatBool(value)
+	case float64:
+		if value == math.Trunc(value) && math.Abs(value) < 1<<53 {
+			return strconv.FormatInt(int64(value), 10)
+		}
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, tomlValue(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(value))
+		for _, key := range keys {
+			if value[key] != nil {
+				items = append(items, tomlKey(key)+" = "+tomlValue(value[key]))
+			}
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	default:
+		return tomlString(fmt.Sprint(value))
+	}
+}
+
+// tomlKey quotes key unless it is a valid bare key.
+func tomlKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return tomlString(key)
+		}
+	}
+	return key
+}
+
+func tomlString(s string) string {
+	builder := &strings.Builder{}
+	builder.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			builder.WriteByte('\\')
+			builder.WriteRune(r)
+		case r == '\n':
+			builder.WriteString(`\n`)
+		case r == '\t':
+			builder.WriteString(`\t`)
+		case r == '\r':
+			builder.WriteString(`\r`)
+		case r < 0x20 || r == 0x7f:
+			_, _ = fmt.Fprintf(builder, `\u%04X`, r)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	builder.WriteByte('"')
+	return builder.String()
+}
@@ -0,0 +1,72 @@
+package errors
+
+import (
+	"encoding/json"
+	"github.com/BurntSushi/toml"
+	"reflect"
+	"testing"
+)
+
+// normalized round-trips value through JSON, so TOML integers and JSON
+// numbers compare equal.
+func normalized(t *testing.T, value interface{}) interface{} {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var object interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		t.Fatal(err)
+	}
+	return object
+}
+
+func TestFormatTOMLRoundTrip(t *testing.T) {
+	source := []byte("server:\n\tport: eighty\n")
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "nested payload",
+			err: Errorf("failure").WithCode(42).WithPayload(map[string]interface{}{
+				"request": map[string]interface{}{"id": "r-1", "retries": 3, "hosts": []string{"a", "b"}},
+				"ratio":   0.5,
+				"ok":      false,
+			}),
+		},
+		{
+			name: "children",
+			err: WrapAll([]error{
+				Errorf("first").WithPayload(map[string]int{"attempt": 1}),
+				Errorf("second").WithKind(KindNotFound),
+			}, "batch"),
+		},
+		{
+			name: "dotted keys",
+			err:  Errorf("invalid port").WithPosition("config.yaml", 2, 8, source),
+		},
+		{
+			name: "control characters",
+			err:  Errorf("quote \" backslash \\ tab \t newline \n bell \a delete \x7f").WithPayload("carriage \r return"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := FormatTOML(tt.err)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded map[string]interface{}
+			if _, err := toml.Decode(document, &decoded); err != nil {
+				t.Fatalf("invalid TOML: %v\n%s", err, document)
+			}
+			got := normalized(t, decoded["error"])
+			want := normalized(t, ZerologDict(tt.err))
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v\nfrom:\n%s", got, want, document)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"sync"
+	"time"
+)
+
+// Tracker detects dependencies oscillating between failing and healthy. It
+// remembers, per key, when the outcome of operations changed; a key is
+// flapping when it changed at least threshold times within window.
+type Tracker struct {
+	window    time.Duration
+	threshold int
+	mu        sync.Mutex
+	keys      map[string]*trackedKey
+}
+
+type trackedKey struct {
+	failing bool
+	changes []time.Time
+}
+
+// NewTracker creates a Tracker flagging keys whose outcome changed at least
+// threshold times within window.
+func NewTracker(window time.Duration, threshold int) *Tracker {
+	return &Tracker{window: window, threshold: threshold, keys: make(map[string]*trackedKey)}
+}
+
+// Observe records the outcome of an operation on key, err being nil on
+// success. Errors observed while key is flapping are returned with the
+// flapping field set, others are returned unchanged.
+func (t *Tracker) Observe(key string, err error) error {
+	now := time.Now()
+	t.mu.Lock()
+	tracked, ok := t.keys[key]
+	if !ok {
+		tracked = &trackedKey{}
+		t.keys[key] = tracked
+	}
+	if failing := err != nil; failing != tracked.failing {
+		tracked.failing = failing
+		tracked.changes = append(tracked.changes, now)
+	}
+	tracked.changes = t.recent(tracked.changes, now)
+	flapping := len(tracked.changes) >= t.threshold
+	if len(tracked.changes) == 0 && !tracked.failing {
+		delete(t.keys, key)
+	}
+	t.mu.Unlock()
+	if err == nil || !flapping {
+		return err
+	}
+	return promote(err).WithField("flapping", true)
+}
+
+// Flapping reports whether key is currently flapping.
+func (t *Tracker) Flapping(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tracked, ok := t.keys[key]
+	if !ok {
+		return false
+	}
+	tracked.changes = t.recent(tracked.changes, time.Now())
+	return len(tracked.changes) >= t.threshold
+}
+
+func (t *Tracker) recent(changes []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-t.window)
+	i := 0
+	for i < len(changes) && changes[i].Before(cutoff) {
+		i++
+	}
+	return changes[i:]
+}
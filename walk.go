@@ -0,0 +1,86 @@
+package errors
+
+import "github.com/jpascal/zap-errors/core"
+
+// Walk visits err and its causes depth-first, calling fn for every error
+// until fn returns false. Unlike errors.Unwrap it branches into every child of
+// join points: errors implementing Unwrap() []error, as built by errors.Join,
+// and the children of errors built by WrapAll.
+func Walk(err error, fn func(error) bool) {
+	walk(err, fn)
+}
+
+func walk(err error, fn func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+	if !fn(err) {
+		return false
+	}
+	switch e := err.(type) {
+	case Error:
+		for _, c := range e.children {
+			if !walk(c.err, fn) {
+				return false
+			}
+		}
+		return walk(e.base.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, cause := range e.Unwrap() {
+			if !walk(cause, fn) {
+				return false
+			}
+		}
+		return true
+	case interface{ Unwrap() error }:
+		return walk(e.Unwrap(), fn)
+	}
+	return true
+}
+
+// asError finds the first Error in the chain of err, see Walk.
+func asError(err error, target *Error) bool {
+	found := false
+	Walk(err, func(err error) bool {
+		*target, found = errorOf(err)
+		return !found
+	})
+	return found
+}
+
+// anyError reports whether fn returns true for an Error in the chain of err,
+// see Walk.
+func anyError(err error, fn func(Error) bool) bool {
+	found := false
+	Walk(err, func(err error) bool {
+		if ee, ok := errorOf(err); ok {
+			found = fn(ee)
+		}
+		return !found
+	})
+	return found
+}
+
+// errorOf returns err as an Error without looking at its causes.
+func errorOf(err error) (Error, bool) {
+	switch e := err.(type) {
+	case Error:
+		return e, true
+	case *Error:
+		if e != nil {
+			return *e, true
+		}
+	case core.Error:
+		return fromCore(e), true
+	}
+	return Error{}, false
+}
+
+// fromCore converts an error of the zap-free core package.
+func fromCore(ce core.Error) Error {
+	ee := Error{base: ce}
+	if stack := ce.StackTrace(); len(stack) > 0 {
+		ee.stacktrace = appendFrames(nil, stack)
+	}
+	return ee
+}
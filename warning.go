@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"fmt"
+	"github.com/jpascal/zap-errors/core"
+	"go.uber.org/zap/zapcore"
+)
+
+// WarningPolicy configures the handling of warnings, see Warningf.
+type WarningPolicy struct {
+	// Stacks captures stacktraces for warnings, subject to StackLevel.
+	Stacks bool
+	// Counted reports logged warnings to the StatsSink, see Stats, so they
+	// count towards error rates.
+	Counted bool
+}
+
+// Warnings sets the policy applied to warnings.
+func Warnings(policy WarningPolicy) Option {
+	return func(c *config) {
+		c.warnings = policy
+	}
+}
+
+// Warningf creates a soft error, for expected and recoverable conditions that
+// are still worth recording. Warnings are logged at warn level, carry no
+// stacktrace and are left out of the StatsSink counters unless the
+// WarningPolicy says otherwise.
+func Warningf(format string, a ...interface{}) Error {
+	statsCreated()
+	ee := Error{
+		base:        core.New(fmt.Sprintf(format, a...), fmt.Errorf(format, a...)),
+		severity:    zapcore.WarnLevel,
+		hasSeverity: true,
+		warning:     true,
+		mark:        newMark(),
+	}
+	if current().warnings.Stacks {
+		ee.stacktrace = stackTraceAt(zapcore.WarnLevel, 0)
+	}
+	return ee
+}
+
+// AsWarning turns the error into a warning, see Warningf. A stacktrace
+// already captured is dropped unless the WarningPolicy keeps stacks.
+func (ee Error) AsWarning() Error {
+	ee.severity = zapcore.WarnLevel
+	ee.hasSeverity = true
+	ee.warning = true
+	if !current().warnings.Stacks {
+		ee.stacktrace = nil
+	}
+	return ee.enriched()
+}
+
+// wantsStack reports whether a missing stacktrace should be captured for ee,
+// which warnings only get when the WarningPolicy keeps stacks.
+func (ee Error) wantsStack() bool {
+	return !ee.warning || current().warnings.Stacks
+}
+
+// IsWarning reports whether the first Error in the chain of err is a warning.
+func IsWarning(err error) bool {
+	var ee Error
+	return asError(err, &ee) && ee.warning
+}
@@ -0,0 +1,222 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WebhookSummary is the JSON document posted by Webhook.
+type WebhookSummary struct {
+	Fingerprint string `json:"fingerprint"`
+	Message     string `json:"message"`
+	Code        int    `json:"code,omitempty"`
+	Kind        Kind   `json:"kind,omitempty"`
+	Level       string `json:"level"`
+	// Occurrences counts the error and its duplicates suppressed since it
+	// was last posted.
+	Occurrences int       `json:"occurrences"`
+	Time        time.Time `json:"time"`
+}
+
+// Webhook posts summaries of errors to a URL, such as a Slack incoming
+// webhook or incident tooling, from a background worker. Errors are reported
+// when they reach a level or have one of the configured kinds, at most once
+// per dedup window for a fingerprint.
+type Webhook struct {
+	logger   *zap.Logger
+	url      string
+	minLevel zapcore.Level
+	kinds    map[Kind]bool
+	client   *http.Client
+	window   time.Duration
+	interval time.Duration
+	queue    chan error
+	mu       sync.RWMutex
+	closed   bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+	done     chan struct{}
+	seen     map[string]*webhookSeen
+}
+
+type webhookSeen struct {
+	postedAt   time.Time
+	suppressed int
+}
+
+// NewWebhook starts a worker posting errors of at least minLevel to url.
+// Reports are dropped while queue errors are waiting. Delivery failures are
+// logged to logger.
+func NewWebhook(logger *zap.Logger, url string, minLevel zapcore.Level, queue int) *Webhook {
+	w := &Webhook{
+		logger:   logger,
+		url:      url,
+		minLevel: minLevel,
+		kinds:    make(map[Kind]bool),
+		client:   http.DefaultClient,
+		window:   time.Minute,
+		queue:    make(chan error, queue),
+		done:     make(chan struct{}),
+		seen:     make(map[string]*webhookSeen),
+	}
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	go w.work()
+	return w
+}
+
+// Kinds makes errors of kinds reported whatever their level. Like the other
+// settings, it must be called before errors are reported.
+func (w *Webhook) Kinds(kinds ...Kind) *Webhook {
+	for _, kind := range kinds {
+		w.kinds[kind] = true
+	}
+	return w
+}
+
+// Dedup sets the window during which an error is not posted again once
+// posted, one minute by default.
+func (w *Webhook) Dedup(window time.Duration) *Webhook {
+	w.window = window
+	return w
+}
+
+// RateLimit makes the worker wait interval between two posts.
+func (w *Webhook) RateLimit(interval time.Duration) *Webhook {
+	w.interval = interval
+	return w
+}
+
+// Client sets the client posting summaries, http.DefaultClient by default.
+func (w *Webhook) Client(client *http.Client) *Webhook {
+	w.client = client
+	return w
+}
+
+// Report queues err if it reaches the thresholds. It never blocks.
+func (w *Webhook) Report(err error) {
+	if err == nil || !w.accepts(err) {
+		return
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return
+	}
+	select {
+	case w.queue <- err:
+	default:
+	}
+}
+
+// Close stops accepting errors and waits for the queued ones to be posted.
+// When ctx is done first, pending posts are abandoned and ctx.Err() is
+// returned.
+func (w *Webhook) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		w.cancel()
+		return ctx.Err()
+	}
+}
+
+func (w *Webhook) accepts(err error) bool {
+	if levelOf(err) >= w.minLevel {
+		return true
+	}
+	var ee Error
+	return asError(err, &ee) && w.kinds[ee.kind]
+}
+
+func (w *Webhook) work() {
+	defer close(w.done)
+	defer w.cancel()
+	for err := range w.queue {
+		if w.ctx.Err() != nil {
+			continue
+		}
+		summary, ok := w.summarize(err)
+		if !ok {
+			continue
+		}
+		if postErr := w.post(summary); postErr != nil {
+			w.logger.Warn("posting error summary", zap.String("url", w.url), zap.Error(postErr))
+		}
+		if w.interval > 0 {
+			timer := time.NewTimer(w.interval)
+			select {
+			case <-timer.C:
+			case <-w.ctx.Done():
+				timer.Stop()
+			}
+		}
+	}
+}
+
+// summarize returns the summary of err, or false when it was posted within
+// the dedup window.
+func (w *Webhook) summarize(err error) (WebhookSummary, bool) {
+	now := time.Now()
+	fingerprint := Fingerprint(err)
+	for key, seen := range w.seen {
+		if now.Sub(seen.postedAt) >= w.window && key != fingerprint {
+			delete(w.seen, key)
+		}
+	}
+	seen, ok := w.seen[fingerprint]
+	if ok && now.Sub(seen.postedAt) < w.window {
+		seen.suppressed++
+		return WebhookSummary{}, false
+	}
+	summary := WebhookSummary{
+		Fingerprint: fingerprint,
+		Message:     err.Error(),
+		Level:       levelOf(err).String(),
+		Occurrences: 1,
+		Time:        now,
+	}
+	if ok {
+		summary.Occurrences += seen.suppressed
+	}
+	var ee Error
+	if asError(err, &ee) {
+		summary.Code = CodeOf(err)
+		summary.Kind = ee.kind
+	}
+	w.seen[fingerprint] = &webhookSeen{postedAt: now}
+	return summary, true
+}
+
+func (w *Webhook) post(summary WebhookSummary) error {
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(w.ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := w.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		return Errorf("%s %s: %s", request.Method, request.URL.Redacted(), response.Status).WithCode(response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// Pool runs tasks on a fixed number of workers. Failed and panicking tasks
+// are turned into Errors carrying the task name, the time spent queued and
+// the run duration, then logged and handed to the collector if any.
+type Pool struct {
+	logger    *zap.Logger
+	collector func(error)
+	tasks     chan poolTask
+	wg        sync.WaitGroup
+}
+
+type poolTask struct {
+	name     string
+	run      func() error
+	queuedAt time.Time
+}
+
+// NewPool starts workers goroutines consuming a queue of size queue.
+func NewPool(logger *zap.Logger, workers, queue int) *Pool {
+	p := &Pool{logger: logger, tasks: make(chan poolTask, queue)}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Collect sets a function receiving every task failure in addition to the
+// logger. It must be called before tasks are submitted.
+func (p *Pool) Collect(collector func(error)) *Pool {
+	p.collector = collector
+	return p
+}
+
+// Submit queues task, blocking while the queue is full.
+func (p *Pool) Submit(name string, task func() error) {
+	p.tasks <- poolTask{name: name, run: task, queuedAt: time.Now()}
+}
+
+// Close stops accepting tasks and waits for the queued ones to finish.
+func (p *Pool) Close() {
+	close(p.tasks)
+	p.wg.Wait()
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for task := range p.tasks {
+		p.run(task)
+	}
+}
+
+func (p *Pool) run(task poolTask) {
+	start := time.Now()
+	err := runTask(task.run)
+	if err == nil {
+		return
+	}
+	ee, ok := err.(Error)
+	if !ok || !ee.panicked {
+		ee = wrap(err, "task "+task.name)
+	}
+	ee = ee.WithFields(map[string]interface{}{
+		"task.name":       task.name,
+		"task.queue_time": start.Sub(task.queuedAt),
+		"task.duration":   time.Since(start),
+	})
+	Log(p.logger, ee)
+	if p.collector != nil {
+		p.collector(ee)
+	}
+}
+
+func runTask(task func() error) (err error) {
+	defer Recover(&err)
+	return task()
+}
@@ -0,0 +1,7 @@
+package errors
+
+// MarshalYAML renders the error like Field does, implementing the Marshaler
+// interfaces of gopkg.in/yaml.v2 and v3.
+func (ee Error) MarshalYAML() (interface{}, error) {
+	return ZerologDict(ee), nil
+}